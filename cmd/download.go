@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/tsunekawa/meroku/internal/downloader"
@@ -21,6 +22,8 @@ func DownloadCmd(args []string) {
 	var wgID string
 	var allFlag bool
 	var withMemberlistFlag bool
+	var archivedFlag bool
+	var archiveURLs string
 	var downloaddir string
 	var report downloader.DownloadReport
 
@@ -28,9 +31,11 @@ func DownloadCmd(args []string) {
 
 	// コマンドラインオプションの設定
 	fs.StringVar(&downloaddir, "dir", defaultDir, "保存先のディレクトリ")
-	fs.StringVar(&wgID, "wgid", "", "ワーキンググループの番号")
+	fs.StringVar(&wgID, "wgid", "", "ワーキンググループのID")
 	fs.BoolVar(&allFlag, "all", false, "すべてのワーキンググループをダウンロードする")
 	fs.BoolVar(&withMemberlistFlag, "memberlist", false, "名簿ページもダウンロードする")
+	fs.BoolVar(&archivedFlag, "archived", false, "設置期間の終了した過去の会議体も対象にする")
+	fs.StringVar(&archiveURLs, "archive-url", "", "過去の会議体一覧ページのURL（カンマ区切りで複数指定可、-archived 指定時のみ有効）")
 	fs.Parse(args)
 
 	// HTMLをダウンロードするフォルダを作成する
@@ -41,9 +46,27 @@ func DownloadCmd(args []string) {
 		}
 	}
 
+	// 表示順をキーとする旧形式のダウンロードディレクトリを移行
+	if err := model.MigrateLegacyDownloadDir(downloaddir); err != nil {
+		log.Fatal(err)
+	}
+
 	// ワーキンググループの一覧を取得
 	workingGroups := model.GetWorkingGroups()
 
+	// 過去の会議体の一覧を取得して追加（現在の一覧と重複するものは現在の情報を優先）
+	if archivedFlag {
+		urls := []string{}
+		if len(archiveURLs) > 0 {
+			urls = strings.Split(archiveURLs, ",")
+		}
+		for id, wg := range model.GetArchivedWorkingGroups(urls) {
+			if _, exists := workingGroups[id]; !exists {
+				workingGroups[id] = wg
+			}
+		}
+	}
+
 	// ワーキンググループ情報をJSONファイルとして保存
 	data, err := json.MarshalIndent(workingGroups, "", "  ")
 	if err != nil {
@@ -65,20 +88,13 @@ func DownloadCmd(args []string) {
 		}
 	} else if wgID != "" {
 		// コマンドラインからワーキンググループ番号を受け取る
-		isValidwgID, _ := regexp.MatchString(`\d{3}`, wgID)
+		isValidwgID, _ := regexp.MatchString(`^[0-9A-Za-z_]+$`, wgID)
 		if !isValidwgID {
 			err := errors.New(wgID + "はワーキンググループ番号でありません。")
 			log.Fatal(err)
 		}
 
-		var wg *model.WorkingGroup
-		wgExists := false
-		for _, item := range workingGroups {
-			if item.ID == wgID {
-				wg = item
-				wgExists = true
-			}
-		}
+		wg, wgExists := workingGroups[wgID]
 
 		if !wgExists {
 			err := errors.New(wgID + "という番号のワーキンググループはありません。")
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/export"
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+)
+
+// exportUsage は、export コマンドの使い方です。
+const exportUsage = `使い方: meroku export <形式> [オプション]
+
+形式:
+  sqlite    SQLite データベース（FTS5 全文検索インデックス付き）
+  tei       TEI XML（ParlaMint 形式に準拠、会議ごとに1文書）
+  kokkai    国会会議録検索システムAPI互換のJSON（会議単位・発言単位）
+  rdf       RDF（Turtle または N-Triples、schema.org・FOAF・LinkedEP の語彙を使用）
+  xlsx      Excel ワークブック（ワーキンググループ・会議・人物・話者・発言のシート）
+  conllu    CoNLL-U（形態素解析済みの発言を1文ずつ）
+`
+
+// ExportCmd は、parse コマンドで出力したデータを各種形式で書き出すためのコマンド関数です。
+func ExportCmd(args []string) {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "sqlite":
+		exportSQLiteCmd(args[1:])
+	case "tei":
+		exportTEICmd(args[1:])
+	case "kokkai":
+		exportKokkaiCmd(args[1:])
+	case "rdf":
+		exportRDFCmd(args[1:])
+	case "xlsx":
+		exportXLSXCmd(args[1:])
+	case "conllu":
+		exportCoNLLUCmd(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, exportUsage)
+		os.Exit(1)
+	}
+}
+
+// loadCorpus は、parse コマンドの出力ディレクトリから Corpus を読み込む関数です。読み込めない場合は終了します。
+func loadCorpus(dir string) *model.Corpus {
+	corpus, err := model.LoadCorpus(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return corpus
+}
+
+// exportSQLiteCmd は、SQLite データベースを書き出すコマンド関数です。
+func exportSQLiteCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("export sqlite", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は <dir>/meroku.db）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "meroku.db")
+	}
+
+	corpus := loadCorpus(dir)
+
+	fmt.Println("Output SQLite Database: " + out)
+	if err := export.SQLite(corpus, out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exportTEICmd は、TEI XML 文書を書き出すコマンド関数です。
+func exportTEICmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("export tei", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のディレクトリ（省略時は <dir>/tei）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "tei")
+	}
+
+	corpus := loadCorpus(dir)
+
+	fmt.Println("Output TEI Documents: " + out)
+	if err := export.TEI(corpus, out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exportKokkaiCmd は、国会会議録検索システムAPI互換のJSONを書き出すコマンド関数です。
+func exportKokkaiCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("export kokkai", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のディレクトリ（省略時は <dir>）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = dir
+	}
+	if err := os.MkdirAll(out, 0777); err != nil {
+		log.Fatal(err)
+	}
+
+	corpus := loadCorpus(dir)
+
+	fmt.Println("Output Kokkai JSON Files: " + out)
+	if err := corpus.Minutes.ExportAsKokkaiJSON(out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exportRDFCmd は、RDF を書き出すコマンド関数です。
+func exportRDFCmd(args []string) {
+	var dir string
+	var out string
+	var format string
+	var base string
+
+	fs := flag.NewFlagSet("export rdf", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は <dir>/meroku.ttl または <dir>/meroku.nt）")
+	fs.StringVar(&format, "format", export.RDFTurtle, "形式 (turtle, ntriples)")
+	fs.StringVar(&base, "base", export.DefaultRDFBase, "IRI の基底")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		if format == export.RDFNTriples {
+			out = filepath.Join(dir, "meroku.nt")
+		} else {
+			out = filepath.Join(dir, "meroku.ttl")
+		}
+	}
+
+	corpus := loadCorpus(dir)
+
+	fmt.Println("Output RDF File: " + out)
+	if err := export.RDF(corpus, out, format, base); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exportXLSXCmd は、Excel ワークブックを書き出すコマンド関数です。
+func exportXLSXCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("export xlsx", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は <dir>/meroku.xlsx）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "meroku.xlsx")
+	}
+
+	corpus := loadCorpus(dir)
+
+	fmt.Println("Output Excel Workbook: " + out)
+	if err := export.XLSX(corpus, out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exportCoNLLUCmd は、CoNLL-U を書き出すコマンド関数です。
+func exportCoNLLUCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("export conllu", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は <dir>/meroku.conllu）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "meroku.conllu")
+	}
+
+	corpus := loadCorpus(dir)
+	tokenizeCorpus(corpus)
+
+	fmt.Println("Output CoNLL-U File: " + out)
+	if err := export.CoNLLU(corpus, out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// tokenizeCorpus は、形態素解析の結果（tokens.jsonl）がない場合に、その場で Corpus の発言を形態素解析する関数です。
+func tokenizeCorpus(corpus *model.Corpus) {
+	if corpus.Minutes.HasTokens() {
+		return
+	}
+
+	log.Println("INFO: tokens.jsonl がないため、形態素解析を行います。繰り返し使う場合は meroku tokenize で保存してください。")
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range corpus.Minutes {
+		if err := tokenizer.TokenizeMinutes(&corpus.Minutes[i]); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// importUsage は、import コマンドの使い方です。
+const importUsage = `使い方: meroku import <形式> [オプション]
+
+形式:
+  kokkai    国会会議録検索システムAPIの応答を保存したJSON（会議単位・発言単位）
+`
+
+// ImportCmd は、外部形式のデータを parse コマンドと同じ形式の出力ディレクトリに取り込むためのコマンド関数です。
+func ImportCmd(args []string) {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, importUsage)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "kokkai":
+		importKokkaiCmd(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, importUsage)
+		os.Exit(1)
+	}
+}
+
+// importKokkaiCmd は、国会会議録形式のJSONを読み込んで出力ディレクトリを作成するコマンド関数です。
+func importKokkaiCmd(args []string) {
+	var in string
+	var outputRootDir string
+
+	fs := flag.NewFlagSet("import kokkai", flag.ExitOnError)
+	fs.StringVar(&in, "in", "", "読み込むJSONファイル、またはJSONファイルを含むディレクトリ")
+	fs.StringVar(&outputRootDir, "out", filepath.Join("data", "kokkai"), "保存先のディレクトリ")
+	fs.Parse(args)
+
+	if len(in) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	minutesArray, err := model.ImportKokkaiJSON(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputdir := filepath.Join(outputRootDir, "output_"+time.Now().Format("2006-01-02T150405"))
+	if err := os.MkdirAll(outputdir, 0777); err != nil {
+		log.Fatal(err)
+	}
+
+	//国会の会議をワーキンググループとして記録
+	wgList := model.WorkingGroupList{}
+	for _, minutes := range minutesArray {
+		if _, exists := wgList[minutes.WorkingGroupID]; !exists {
+			wgList[minutes.WorkingGroupID] = model.WorkingGroup{ID: minutes.WorkingGroupID, Name: minutes.WorkingGroup}
+		}
+	}
+	if err := wgList.Save(filepath.Join(outputdir, "working-groups.json")); err != nil {
+		log.Fatal(err)
+	}
+
+	jsonlWriter, err := model.NewJSONLWriter(outputdir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range minutesArray {
+		if err := jsonlWriter.WriteMinutes(&minutesArray[i]); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := jsonlWriter.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Imported %v Minutes: %v\n", len(minutesArray), outputdir)
+	minutesArray.ExportAsJSON(outputdir)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/search"
+)
+
+// IndexCmd は、parse コマンドで出力した議事録の発言から全文検索インデックスを作成するためのコマンド関数です。
+func IndexCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "検索インデックスの保存先（省略時は <dir>/"+search.IndexDirName+"）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, search.IndexDirName)
+	}
+
+	minutesArray, err := model.LoadMinutesArray(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := search.BuildIndex(minutesArray, out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Indexed %v Speeches.\n", count)
+}
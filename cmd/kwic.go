@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+)
+
+// KWICCmd は、parse コマンドで出力した議事録から語を検索し、前後の文脈と話者・会議を KWIC (Keyword in Context) 形式で表示するためのコマンド関数です。
+func KWICCmd(args []string) {
+	var dir string
+	var out string
+	var term string
+	var isRegexp bool
+	var contextLength int
+	var wgIDs string
+	var withChildren bool
+	var speaker string
+	var roleClass string
+	var from string
+	var to string
+	var agenda string
+	var format string
+
+	fs := flag.NewFlagSet("kwic", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は標準出力）")
+	fs.StringVar(&term, "term", "", "検索語（引数で指定することもできます）")
+	fs.BoolVar(&isRegexp, "regexp", false, "検索語を正規表現として扱う")
+	fs.IntVar(&contextLength, "context", analysis.DefaultKWICContext, "前後に表示する文脈の文字数")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.BoolVar(&withChildren, "children", false, "-wg で指定したワーキンググループの下位の会議体も対象にする")
+	fs.StringVar(&speaker, "speaker", "", "対象とする話者（話者ラベル・人物ID・氏名）")
+	fs.StringVar(&roleClass, "role", "", "対象とする話者の区分（member, secretariat, other）")
+	fs.StringVar(&from, "from", "", "対象とする開催日の始まり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&to, "to", "", "対象とする開催日の終わり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&agenda, "agenda", "", "対象とする議題の番号（カンマ区切り、0 は最初の議題より前の発言）")
+	fs.StringVar(&format, "format", "table", "出力形式（table, csv）")
+	fs.Parse(args)
+
+	if len(term) <= 0 && fs.NArg() > 0 {
+		term = fs.Arg(0)
+	}
+	if len(dir) <= 0 || len(term) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	pattern, err := analysis.CompileKWICPattern(term, isRegexp)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	corpus := loadCorpus(dir)
+	filter := analysis.NewFilter(corpus.WorkingGroups, wgIDs, withChildren, speaker, roleClass, from, to)
+	agendaItems, err := analysis.ParseAgendaItems(agenda)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter.AgendaItems = agendaItems
+	hits := analysis.KWIC(corpus, pattern, filter, contextLength)
+
+	w := io.Writer(os.Stdout)
+	if len(out) > 0 {
+		file, err := os.Create(out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case "table":
+		// 検索語の位置がそろうように、左の文脈を右寄せにする
+		rows := [][]string{}
+		for _, hit := range hits {
+			rows = append(rows, []string{hit.MeetingID, hit.Date, hit.SpeakerLabel, hit.PersonID, hit.Left, "【" + hit.Keyword + "】", hit.Right})
+		}
+		err = analysis.WriteTable(w, []string{"meeting_id", "date", "speaker", "person_id", "left", "keyword", "right"}, rows, map[int]bool{4: true})
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write(analysis.KWICHeader)
+		for _, hit := range hits {
+			writer.Write(hit.Strings())
+		}
+		writer.Flush()
+		err = writer.Error()
+	default:
+		log.Fatal("出力形式 " + format + " には対応していません。")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+)
+
+// NetworkCmd は、parse コマンドで出力した議事録から、話者の発言順のネットワークや同じ会議での発言のネットワークを書き出すためのコマンド関数です。
+func NetworkCmd(args []string) {
+	var dir string
+	var out string
+	var networkType string
+	var format string
+	var wgIDs string
+	var withChildren bool
+	var from string
+	var to string
+	var includeUnresolved bool
+	var perWG bool
+
+	fs := flag.NewFlagSet("network", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は標準出力、-per-wg 指定時は保存先のディレクトリ）")
+	fs.StringVar(&networkType, "type", analysis.NetworkTurn, "ネットワークの種類（turn: 発言順の有向グラフ, coattendance: 同じ会議で発言した人物の無向グラフ）")
+	fs.StringVar(&format, "format", analysis.GraphFormatGraphML, "出力形式（graphml, gexf, csv）")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.BoolVar(&withChildren, "children", false, "-wg で指定したワーキンググループの下位の会議体も対象にする")
+	fs.StringVar(&from, "from", "", "対象とする開催日の始まり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&to, "to", "", "対象とする開催日の終わり（YYYY-MM-DD、前方一致）")
+	fs.BoolVar(&includeUnresolved, "unresolved", false, "名寄せされていない話者（事務局など）も話者ラベルで頂点に含める")
+	fs.BoolVar(&perWG, "per-wg", false, "ワーキンググループごとに <out>/<type>_<ワーキンググループID>.<format> として書き出す")
+	fs.Parse(args)
+
+	if len(dir) <= 0 || (perWG && len(out) <= 0) {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	corpus := loadCorpus(dir)
+	filter := analysis.NewFilter(corpus.WorkingGroups, wgIDs, withChildren, "", "", from, to)
+
+	if !perWG {
+		graph, err := analysis.Network(corpus, networkType, filter, includeUnresolved)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := io.Writer(os.Stdout)
+		if len(out) > 0 {
+			file, err := os.Create(out)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+			w = file
+		}
+		if err := graph.Write(w, format); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	ids := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if filter.MatchMinutes(&minutes) {
+			ids[minutes.WorkingGroupID] = true
+		}
+	}
+	sortedIDs := []string{}
+	for id := range ids {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	for _, id := range sortedIDs {
+		wgFilter := filter
+		wgFilter.WorkingGroupIDs = map[string]bool{id: true}
+		graph, err := analysis.Network(corpus, networkType, wgFilter, includeUnresolved)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		file, err := os.Create(filepath.Join(out, networkType+"_"+id+"."+format))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := graph.Write(file, format); err != nil {
+			log.Fatal(err)
+		}
+		file.Close()
+	}
+	fmt.Printf("Output %v Networks.\n", len(sortedIDs))
+}
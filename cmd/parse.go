@@ -6,10 +6,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
 	"time"
 
 	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+	"github.com/tsunekawa/meroku/internal/search"
 )
 
 // ParseCmd は、議事録をダウンロードするためのコマンド関数です。
@@ -19,6 +20,10 @@ func ParseCmd(args []string) {
 	var outputRootDir string
 	var baseDirs []string
 	var withMemberlistFlag bool
+	var encodingName string
+	var speechFormat string
+	var tokenizeFlag bool
+	var indexFlag bool
 
 	defaultDir := "./data/example"
 	defaultOutputDir := filepath.Join(defaultDir, "json")
@@ -28,8 +33,17 @@ func ParseCmd(args []string) {
 	fs.StringVar(&rootDir, "dir", defaultDir, "読み込み元のディレクトリ")
 	fs.StringVar(&outputRootDir, "out", defaultOutputDir, "保存先のディレクトリ")
 	fs.BoolVar(&withMemberlistFlag, "memberlist", false, "名簿ページもパースする")
+	fs.StringVar(&encodingName, "encoding", "cp932", "発話者リストCSVとKH Coder用ファイル（本文・外部変数）の文字コード (utf-8, utf-8-bom, shift_jis, cp932)")
+	fs.StringVar(&speechFormat, "speech-format", "csv", "発言単位の表の形式 (csv, tsv)")
+	fs.BoolVar(&tokenizeFlag, "tokenize", false, "発言を形態素解析して tokens.jsonl に保存する")
+	fs.BoolVar(&indexFlag, "index", false, "発言の全文検索インデックスを作成する")
 	fs.Parse(args)
 
+	csvEncoding, err := model.ParseCSVEncoding(encodingName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// ディレクトリがあればインポート対象に加える
 	if _, err := os.Stat(filepath.Join(rootDir, "html")); err == nil {
 		baseDirs = append(baseDirs, filepath.Join(rootDir, "html"))
@@ -40,6 +54,11 @@ func ParseCmd(args []string) {
 	
 	outputdir := filepath.Join(outputRootDir, "output_"+time.Now().Format("2006-01-02T150405"))
 
+	//表示順をキーとする旧形式のダウンロードディレクトリを移行
+	if err := model.MigrateLegacyDownloadDir(rootDir); err != nil {
+		log.Fatal(err)
+	}
+
 	//ダウンローダーで出力したワーキンググループリストを読み込み
 	wgList := model.ImportWorkingGroupList(filepath.Join(rootDir, "working-groups.json"))
 
@@ -51,7 +70,16 @@ func ParseCmd(args []string) {
 		}
 	}
 
-	minutesArray := model.ImportMinutesArrayFromHTML(baseDirs, outputdir)
+	//出力ディレクトリだけで後続のコマンド（export など）を実行できるよう、ワーキンググループリストを保存
+	if err := wgList.Save(filepath.Join(outputdir, "working-groups.json")); err != nil {
+		log.Fatal(err)
+	}
+
+	//取得元URLなどの来歴情報を、ダウンロードレポートとワーキンググループリストから集める
+	sources := model.LoadSourceIndex(rootDir, wgList)
+
+	// パースした議事録ごとに実行する処理（名寄せ、JSON Linesへの書き出し）
+	handlers := []model.MinutesHandler{}
 
 	//名簿のパースと出力(--memberlistオプション指定時のみ実行)
 	if withMemberlistFlag {
@@ -64,17 +92,24 @@ func ParseCmd(args []string) {
 				log.Fatal(err)
 			}
 
-			memberListMap := make(map[string]*model.MemberList)
-			WGNOPATTERN := regexp.MustCompile("no([0-9]{2})")
+			memberListMap := model.MemberListMap{}
 
 			for _, file := range files  {
-				wgno := WGNOPATTERN.FindAllStringSubmatch(file, -1)[0][1]
+				wgID, ok := model.WorkingGroupIDFromFileName(file)
+				if !ok {
+					log.Printf("WARN: %v からワーキンググループIDを読み取れません。\n", file)
+					continue
+				}
 
 				memberlist, err := model.LoadMemberListFromHTML(file)
 				if err != nil {
 					log.Fatal(err)
 				}
-				memberListMap[wgno] = &memberlist
+				sources.Apply(&memberlist.Provenance)
+				if wg, exists := wgList[wgID]; exists {
+					memberlist.WorkingGroup = &wg
+				}
+				memberListMap[wgID] = &memberlist
 
 				filePath := filepath.Join(outputdir, "memberlist", filepath.Base(file[:len(file)-len(filepath.Ext(file))]) + ".json")
 
@@ -86,25 +121,54 @@ func ParseCmd(args []string) {
 				fp.WriteString(string(memberlist.ToJSON()))
 			}
 
-			resolutedMinutesArray := model.MinutesArray{}
-			for _, minutes := range minutesArray {
-				for _, speaker := range minutes.Speakers {
-					memberlist, exists := memberListMap[minutes.WorkingGroupOrder]
-					if exists {
-						person, sims, err := memberlist.Resolve(speaker.Label)
-						if err != nil {
-							log.Println(err)
-						} else {
-							speaker.Person = *person
-							speaker.ResolutionScore = sims[0].Score
-							minutes.Speakers[speaker.Label] = speaker
-							log.Println("名寄せ：" + speaker.Label + "(" +speaker.Person.ID + ")")
-						}
-					}
-				}
-				resolutedMinutesArray = append(resolutedMinutesArray,  minutes)
-			}
-			minutesArray = resolutedMinutesArray
+			handlers = append(handlers, memberListMap.ResolveSpeakers)
+		}
+	}
+
+	//議事録単位・発言単位のJSON Linesを、パースしながら書き出す
+	jsonlWriter, err := model.NewJSONLWriter(outputdir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handlers = append(handlers, jsonlWriter.WriteMinutes)
+
+	//形態素解析と結果の書き出し(--tokenizeオプション指定時のみ実行)
+	var tokenWriter *model.TokenWriter
+	if tokenizeFlag {
+		tokenizer, err := morph.NewTokenizer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		tokenWriter, err = model.NewTokenWriter(outputdir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		handlers = append(handlers, tokenizer.TokenizeMinutes, tokenWriter.WriteMinutes)
+	}
+
+	//全文検索インデックスの作成(--indexオプション指定時のみ実行)
+	var indexer *search.Indexer
+	if indexFlag {
+		indexer, err = search.NewIndexer(filepath.Join(outputdir, search.IndexDirName))
+		if err != nil {
+			log.Fatal(err)
+		}
+		handlers = append(handlers, indexer.IndexMinutes)
+	}
+
+	minutesArray := model.ImportMinutesArrayFromHTML(baseDirs, outputdir, sources, handlers...)
+
+	if err := jsonlWriter.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if tokenWriter != nil {
+		if err := tokenWriter.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if indexer != nil {
+		if err := indexer.Close(); err != nil {
+			log.Fatal(err)
 		}
 	}
 
@@ -113,9 +177,18 @@ func ParseCmd(args []string) {
 
 	//CSVで発話者リストを書き出し（動作検証用）
 	fmt.Println("Output Speaker CSV File.")
-	minutesArray.ExportSpeakersAsCSV(outputdir)
+	if _, err := minutesArray.ExportSpeakersAsCSV(outputdir, csvEncoding); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("Output Speech Table File.")
+	if err := minutesArray.ExportSpeechesAsTable(outputdir, speechFormat); err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Println("Output KHCoder Source File.")
-	minutesArray.ExportAsKH(wgList, outputdir)
+	if _, err := minutesArray.ExportAsKH(wgList, outputdir, csvEncoding); err != nil {
+		log.Fatal(err)
+	}
 
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/search"
+)
+
+// SearchCmd は、index コマンドで作成した全文検索インデックスから発言を検索するためのコマンド関数です。
+func SearchCmd(args []string) {
+	var dir string
+	var indexPath string
+	var wgIDs string
+	var speaker string
+	var roleClass string
+	var year string
+	var size int
+	var from int
+	var facets string
+	var format string
+
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&indexPath, "index", "", "検索インデックス（省略時は <dir>/"+search.IndexDirName+"）")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.StringVar(&speaker, "speaker", "", "対象とする話者（話者ラベル・人物ID・氏名）")
+	fs.StringVar(&roleClass, "role", "", "対象とする話者の区分（member, secretariat, other）")
+	fs.StringVar(&year, "year", "", "対象とする開催年")
+	fs.IntVar(&size, "size", search.DefaultSize, "表示する件数")
+	fs.IntVar(&from, "from", 0, "表示を始める位置（0から）")
+	fs.StringVar(&facets, "facets", "", "集計するファセット（wg, speaker, role, year のカンマ区切り）")
+	fs.StringVar(&format, "format", "text", "出力形式（text, json）")
+	fs.Parse(args)
+
+	if len(indexPath) <= 0 && len(dir) > 0 {
+		indexPath = filepath.Join(dir, search.IndexDirName)
+	}
+	if len(indexPath) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	req := search.Request{Query: strings.Join(fs.Args(), " "), Speaker: speaker, RoleClass: roleClass, Year: year, Size: size, From: from}
+	for _, id := range strings.Split(wgIDs, ",") {
+		if id = strings.TrimSpace(id); len(id) > 0 {
+			req.WorkingGroupIDs = append(req.WorkingGroupIDs, id)
+		}
+	}
+	for _, name := range strings.Split(facets, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			req.Facets = append(req.Facets, name)
+		}
+	}
+
+	index, err := search.Open(indexPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer index.Close()
+
+	result, err := index.Search(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch format {
+	case "text":
+		fmt.Printf("%v件中 %v～%v件目\n\n", result.Total, from+1, from+len(result.Hits))
+		for _, hit := range result.Hits {
+			fmt.Printf("%v #%v %v %v (%.3f)\n", hit.MeetingID, hit.Turn, hit.Date, hit.SpeakerLabel, hit.Score)
+			fragments := hit.Fragments
+			if len(fragments) <= 0 {
+				fragments = []string{hit.Text}
+			}
+			for _, fragment := range fragments {
+				fmt.Println("  " + strings.ReplaceAll(search.PlainFragment(fragment), "\n", " "))
+			}
+			fmt.Println()
+		}
+
+		names := []string{}
+		for name := range result.Facets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println("[" + name + "]")
+			for _, count := range result.Facets[name] {
+				fmt.Printf("  %v: %v\n", count.Term, count.Count)
+			}
+		}
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatal("出力形式 " + format + " には対応していません。")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/search"
+	"github.com/tsunekawa/meroku/internal/server"
+)
+
+// ServeCmd は、parse コマンドで出力したデータを読み取り専用の REST/JSON API として提供するためのコマンド関数です。
+func ServeCmd(args []string) {
+	var dir string
+	var addr string
+	var indexPath string
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&addr, "addr", "127.0.0.1:8080", "待ち受けるアドレス")
+	fs.StringVar(&indexPath, "index", "", "検索インデックス（省略時は <dir>/"+search.IndexDirName+"、存在しない場合は検索の API を無効にする）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	corpus := loadCorpus(dir)
+
+	if len(indexPath) <= 0 {
+		indexPath = filepath.Join(dir, search.IndexDirName)
+	}
+	var index *search.Index
+	if _, err := os.Stat(indexPath); err == nil {
+		index, err = search.Open(indexPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer index.Close()
+	} else {
+		log.Printf("WARN: 検索インデックス %v がないため、検索の API は使用できません。\n", indexPath)
+	}
+
+	fmt.Printf("Serving %v Minutes on http://%v/ (OpenAPI: http://%v/openapi.json)\n", len(corpus.Minutes), addr, addr)
+	log.Fatal(http.ListenAndServe(addr, server.New(corpus, index)))
+}
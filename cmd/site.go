@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/site"
+)
+
+// SiteCmd は、parse コマンドで出力したデータから閲覧用の静的サイトを作成するためのコマンド関数です。
+func SiteCmd(args []string) {
+	var dir string
+	var out string
+
+	fs := flag.NewFlagSet("site", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のディレクトリ（省略時は <dir>/site）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "site")
+	}
+
+	corpus := loadCorpus(dir)
+
+	if err := site.Generate(corpus, out); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Output Static Site: " + filepath.Join(out, "index.html"))
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+)
+
+// StatsCmd は、parse コマンドで出力した議事録から、話者・会議ごとの発言数や発言文字数などの統計を集計するためのコマンド関数です。
+func StatsCmd(args []string) {
+	var dir string
+	var out string
+	var by string
+	var wgIDs string
+	var withChildren bool
+	var speaker string
+	var roleClass string
+	var from string
+	var to string
+	var agenda string
+	var format string
+
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は標準出力）")
+	fs.StringVar(&by, "by", analysis.GroupByPerson, "集計の単位（"+strings.Join(analysis.StatsGroups, ", ")+"）")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.BoolVar(&withChildren, "children", false, "-wg で指定したワーキンググループの下位の会議体も対象にする")
+	fs.StringVar(&speaker, "speaker", "", "対象とする話者（話者ラベル・人物ID・氏名）")
+	fs.StringVar(&roleClass, "role", "", "対象とする話者の区分（member, secretariat, other）")
+	fs.StringVar(&from, "from", "", "対象とする開催日の始まり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&to, "to", "", "対象とする開催日の終わり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&agenda, "agenda", "", "対象とする議題の番号（カンマ区切り、0 は最初の議題より前の発言）")
+	fs.StringVar(&format, "format", "table", "出力形式（table, json, csv）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	corpus := loadCorpus(dir)
+	filter := analysis.NewFilter(corpus.WorkingGroups, wgIDs, withChildren, speaker, roleClass, from, to)
+	agendaItems, err := analysis.ParseAgendaItems(agenda)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter.AgendaItems = agendaItems
+	rows, err := analysis.Stats(corpus, by, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if len(out) > 0 {
+		file, err := os.Create(out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case "table":
+		table := [][]string{}
+		for _, row := range rows {
+			table = append(table, row.Strings())
+		}
+		rightAligned := map[int]bool{}
+		for i := 2; i < len(analysis.StatsHeader); i++ {
+			rightAligned[i] = true
+		}
+		err = analysis.WriteTable(w, analysis.StatsHeader, table, rightAligned)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "    ")
+		err = encoder.Encode(rows)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write(analysis.StatsHeader)
+		for _, row := range rows {
+			writer.Write(row.Strings())
+		}
+		writer.Flush()
+		err = writer.Error()
+	default:
+		log.Fatal("出力形式 " + format + " には対応していません。")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+)
+
+// TermsCmd は、parse コマンドで出力した議事録から、話者・ワーキンググループ・期間ごとの語の出現回数や TF-IDF を集計し、CSV で書き出すためのコマンド関数です。
+func TermsCmd(args []string) {
+	var dir string
+	var out string
+	var by string
+	var unit string
+	var n int
+	var measure string
+	var top int
+	var minCount int
+	var pos string
+	var stopwordsPath string
+	var noDefaultStopwords bool
+	var wgIDs string
+	var withChildren bool
+	var speaker string
+	var roleClass string
+	var from string
+	var to string
+	var agenda string
+
+	fs := flag.NewFlagSet("terms", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のファイル（省略時は標準出力）")
+	fs.StringVar(&by, "by", analysis.GroupByAll, "集計の単位（"+strings.Join(analysis.TermsGroups, ", ")+"）")
+	fs.StringVar(&unit, "unit", analysis.TermUnitToken, "語の単位（token: 形態素解析した語の原形, char: 文字）")
+	fs.IntVar(&n, "n", 1, "n-gram の n")
+	fs.StringVar(&measure, "measure", analysis.TermMeasureCount, "並べ替えの基準（count, tfidf）")
+	fs.IntVar(&top, "top", 100, "集計の単位ごとに書き出す語の数（0 の場合はすべて）")
+	fs.IntVar(&minCount, "min-count", 1, "集計の単位ごとの出現回数がこれより少ない語を除く")
+	fs.StringVar(&pos, "pos", strings.Join(analysis.DefaultTermPOS, ","), "-unit token で数える品詞（前方一致、カンマ区切り、空の場合は記号以外すべて）")
+	fs.StringVar(&stopwordsPath, "stopwords", "", "ストップワードのファイル（1行に1語）")
+	fs.BoolVar(&noDefaultStopwords, "no-default-stopwords", false, "-unit token で既定のストップワードを使わない")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.BoolVar(&withChildren, "children", false, "-wg で指定したワーキンググループの下位の会議体も対象にする")
+	fs.StringVar(&speaker, "speaker", "", "対象とする話者（話者ラベル・人物ID・氏名）")
+	fs.StringVar(&roleClass, "role", "", "対象とする話者の区分（member, secretariat, other）")
+	fs.StringVar(&from, "from", "", "対象とする開催日の始まり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&to, "to", "", "対象とする開催日の終わり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&agenda, "agenda", "", "対象とする議題の番号（カンマ区切り、0 は最初の議題より前の発言）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	options := analysis.TermOptions{GroupBy: by, Unit: unit, N: n, MinCount: minCount, Stopwords: map[string]bool{}}
+	for _, p := range strings.Split(pos, ",") {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			options.POS = append(options.POS, p)
+		}
+	}
+	if unit == analysis.TermUnitToken && !noDefaultStopwords {
+		for _, word := range analysis.DefaultStopwords {
+			options.Stopwords[word] = true
+		}
+	}
+	if len(stopwordsPath) > 0 {
+		stopwords, err := analysis.LoadStopwords(stopwordsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, word := range stopwords {
+			options.Stopwords[word] = true
+		}
+	}
+
+	corpus := loadCorpus(dir)
+	if unit == analysis.TermUnitToken {
+		tokenizeCorpus(corpus)
+	}
+	filter := analysis.NewFilter(corpus.WorkingGroups, wgIDs, withChildren, speaker, roleClass, from, to)
+	agendaItems, err := analysis.ParseAgendaItems(agenda)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter.AgendaItems = agendaItems
+
+	rows, err := analysis.Terms(corpus, filter, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if rows, err = analysis.TopTerms(rows, measure, top); err != nil {
+		log.Fatal(err)
+	}
+	if measure == analysis.TermMeasureTFIDF && by == analysis.GroupByAll {
+		log.Println("WARN: -by all では集計の単位がひとつのため、TF-IDF はすべて 0 になります。")
+	}
+
+	w := io.Writer(os.Stdout)
+	if len(out) > 0 {
+		file, err := os.Create(out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(analysis.TermsHeader)
+	for _, row := range rows {
+		writer.Write(row.Strings())
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Fatal(err)
+	}
+}
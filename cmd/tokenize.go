@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+)
+
+// TokenizeCmd は、parse コマンドで出力した議事録の発言を形態素解析し、出力ディレクトリに tokens.jsonl を保存するためのコマンド関数です。
+func TokenizeCmd(args []string) {
+	var dir string
+
+	fs := flag.NewFlagSet("tokenize", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	minutesArray, err := model.LoadMinutesArray(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokenWriter, err := model.NewTokenWriter(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i := range minutesArray {
+		if err := tokenizer.TokenizeMinutes(&minutesArray[i]); err != nil {
+			log.Fatal(err)
+		}
+		if err := tokenWriter.WriteMinutes(&minutesArray[i]); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := tokenWriter.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Tokenized %v Minutes.\n", len(minutesArray))
+}
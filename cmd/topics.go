@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+)
+
+// TopicsCmd は、parse コマンドで出力した議事録の発言からトピックモデル（LDA）を推定し、結果を CSV で書き出すためのコマンド関数です。
+func TopicsCmd(args []string) {
+	var dir string
+	var out string
+	var k int
+	var iterations int
+	var alpha float64
+	var beta float64
+	var seed int64
+	var topWords int
+	var pos string
+	var stopwordsPath string
+	var noDefaultStopwords bool
+	var wgIDs string
+	var withChildren bool
+	var speaker string
+	var roleClass string
+	var from string
+	var to string
+	var agenda string
+
+	fs := flag.NewFlagSet("topics", flag.ExitOnError)
+	fs.StringVar(&dir, "dir", "", "parse コマンドの出力ディレクトリ")
+	fs.StringVar(&out, "out", "", "保存先のディレクトリ（省略時は <dir>/topics）")
+	fs.IntVar(&k, "k", 10, "トピックの数")
+	fs.IntVar(&iterations, "iterations", 1000, "ギブスサンプリングの反復回数")
+	fs.Float64Var(&alpha, "alpha", 0, "文書ごとのトピック分布の事前分布のパラメータ（0 の場合は 50/K）")
+	fs.Float64Var(&beta, "beta", 0, "トピックごとの語の分布の事前分布のパラメータ（0 の場合は 0.1）")
+	fs.Int64Var(&seed, "seed", 1, "乱数の種")
+	fs.IntVar(&topWords, "top", 20, "トピックごとに書き出す語の数")
+	fs.StringVar(&pos, "pos", strings.Join(analysis.DefaultTermPOS, ","), "数える品詞（前方一致、カンマ区切り、空の場合は記号以外すべて）")
+	fs.StringVar(&stopwordsPath, "stopwords", "", "ストップワードのファイル（1行に1語）")
+	fs.BoolVar(&noDefaultStopwords, "no-default-stopwords", false, "既定のストップワードを使わない")
+	fs.StringVar(&wgIDs, "wg", "", "対象とするワーキンググループID（カンマ区切り）")
+	fs.BoolVar(&withChildren, "children", false, "-wg で指定したワーキンググループの下位の会議体も対象にする")
+	fs.StringVar(&speaker, "speaker", "", "対象とする話者（話者ラベル・人物ID・氏名）")
+	fs.StringVar(&roleClass, "role", "", "対象とする話者の区分（member, secretariat, other）")
+	fs.StringVar(&from, "from", "", "対象とする開催日の始まり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&to, "to", "", "対象とする開催日の終わり（YYYY-MM-DD、前方一致）")
+	fs.StringVar(&agenda, "agenda", "", "対象とする議題の番号（カンマ区切り、0 は最初の議題より前の発言）")
+	fs.Parse(args)
+
+	if len(dir) <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(out) <= 0 {
+		out = filepath.Join(dir, "topics")
+	}
+
+	options := analysis.LDAOptions{K: k, Iterations: iterations, Alpha: alpha, Beta: beta, Seed: seed}
+	options.Terms = analysis.TermOptions{Unit: analysis.TermUnitToken, N: 1, Stopwords: map[string]bool{}}
+	for _, p := range strings.Split(pos, ",") {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			options.Terms.POS = append(options.Terms.POS, p)
+		}
+	}
+	if !noDefaultStopwords {
+		for _, word := range analysis.DefaultStopwords {
+			options.Terms.Stopwords[word] = true
+		}
+	}
+	if len(stopwordsPath) > 0 {
+		stopwords, err := analysis.LoadStopwords(stopwordsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, word := range stopwords {
+			options.Terms.Stopwords[word] = true
+		}
+	}
+
+	corpus := loadCorpus(dir)
+	tokenizeCorpus(corpus)
+	filter := analysis.NewFilter(corpus.WorkingGroups, wgIDs, withChildren, speaker, roleClass, from, to)
+	agendaItems, err := analysis.ParseAgendaItems(agenda)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter.AgendaItems = agendaItems
+
+	lda, err := analysis.LDA(corpus, filter, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err := lda.ExportCSV(out, topWords); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Output %v Topics (%v Words) to %v.\n", lda.K, len(lda.Vocabulary), out)
+}
@@ -0,0 +1,101 @@
+// Package analysis は、パース済みの議事録を集計・分析するためのパッケージです。
+package analysis
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// Filter は、分析の対象とする発言の条件を表す構造体です。空の項目は条件に使いません。
+type Filter struct {
+	// WorkingGroupIDs は、対象とするワーキンググループIDの集合です。
+	WorkingGroupIDs map[string]bool
+	// Speaker は、話者ラベル・人物ID・氏名のいずれかと一致する発言だけを対象にします。
+	Speaker string
+	// RoleClass は、話者の区分（member, secretariat, other）です。
+	RoleClass string
+	// From と To は、開催日の範囲（YYYY-MM-DD の前方一致で比較）です。
+	From string
+	To   string
+	// AgendaItems は、対象とする議題の番号の集合です。0 は最初の議題に移るまでの発言を表します。
+	AgendaItems map[int]bool
+}
+
+// NewFilter は、ワーキンググループIDのカンマ区切りの一覧などから Filter を作成する関数です。
+// withChildren が true の場合は、指定したワーキンググループの下位の会議体も対象にします。
+func NewFilter(wgList model.WorkingGroupList, wgIDs string, withChildren bool, speaker string, roleClass string, from string, to string) Filter {
+	filter := Filter{Speaker: speaker, RoleClass: roleClass, From: from, To: to}
+
+	for _, id := range strings.Split(wgIDs, ",") {
+		id = strings.TrimSpace(id)
+		if len(id) <= 0 {
+			continue
+		}
+		if filter.WorkingGroupIDs == nil {
+			filter.WorkingGroupIDs = map[string]bool{}
+		}
+		filter.WorkingGroupIDs[id] = true
+		if withChildren {
+			for _, childID := range wgList.Descendants(id) {
+				filter.WorkingGroupIDs[childID] = true
+			}
+		}
+	}
+
+	return filter
+}
+
+// ParseAgendaItems は、議題の番号のカンマ区切りの一覧から、Filter.AgendaItems に指定する集合を作成する関数です。空の場合は nil を返します。
+func ParseAgendaItems(list string) (map[int]bool, error) {
+	var items map[int]bool
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if len(value) <= 0 {
+			continue
+		}
+		item, err := strconv.Atoi(value)
+		if err != nil || item < 0 {
+			return nil, errors.New("議題の番号 " + value + " は0以上の整数ではありません。")
+		}
+		if items == nil {
+			items = map[int]bool{}
+		}
+		items[item] = true
+	}
+
+	return items, nil
+}
+
+// MatchMinutes は、議事録が会議単位の条件（ワーキンググループと開催日）を満たすかを判定するメソッドです。
+func (filter Filter) MatchMinutes(minutes *model.Minutes) bool {
+	if filter.WorkingGroupIDs != nil && !filter.WorkingGroupIDs[minutes.WorkingGroupID] {
+		return false
+	}
+	if len(filter.From) > 0 && (len(minutes.Date) <= 0 || minutes.Date < filter.From) {
+		return false
+	}
+	// 「2020」や「2020-09」のような前方一致の指定でも、その期間の終わりまでを含める
+	if len(filter.To) > 0 && (len(minutes.Date) <= 0 || (minutes.Date > filter.To && !strings.HasPrefix(minutes.Date, filter.To))) {
+		return false
+	}
+
+	return true
+}
+
+// MatchSpeaker は、話者が話者単位の条件（話者と区分）を満たすかを判定するメソッドです。
+func (filter Filter) MatchSpeaker(speaker *model.Speaker) bool {
+	if speaker == nil {
+		return false
+	}
+	if len(filter.Speaker) > 0 && filter.Speaker != speaker.Label && filter.Speaker != speaker.Person.ID && filter.Speaker != speaker.Person.Name {
+		return false
+	}
+	if len(filter.RoleClass) > 0 && filter.RoleClass != model.RoleClass(speaker) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// DefaultKWICContext は、KWIC で表示する前後の文脈の既定の文字数です。
+const DefaultKWICContext = 20
+
+// KWICHit は、KWIC 検索で見つかった語1件と、その前後の文脈・話者・会議を表す構造体です。
+type KWICHit struct {
+	MeetingID      string
+	WorkingGroupID string
+	Date           string
+	Turn           int
+	SpeakerLabel   string
+	PersonID       string
+	Left           string
+	Keyword        string
+	Right          string
+}
+
+// KWICHeader は、KWICHit を表として書き出す際の列名です。
+var KWICHeader = []string{"meeting_id", "wg_id", "date", "turn", "speaker_label", "person_id", "left", "keyword", "right"}
+
+// Strings は、KWICHit を表の1行分の文字列の配列に変換するメソッドです。
+func (hit KWICHit) Strings() []string {
+	return []string{
+		hit.MeetingID,
+		hit.WorkingGroupID,
+		hit.Date,
+		strconv.Itoa(hit.Turn),
+		hit.SpeakerLabel,
+		hit.PersonID,
+		hit.Left,
+		hit.Keyword,
+		hit.Right,
+	}
+}
+
+// CompileKWICPattern は、検索語を正規表現に変換する関数です。isRegexp が false の場合は、検索語をそのままの文字列として検索します。
+func CompileKWICPattern(term string, isRegexp bool) (*regexp.Regexp, error) {
+	if !isRegexp {
+		term = regexp.QuoteMeta(term)
+	}
+
+	return regexp.Compile(term)
+}
+
+// KWIC は、filter の条件を満たす発言から pattern に一致する箇所をすべて探し、前後 contextLength 文字の文脈とともに返す関数です。
+// 段落の区切り（改行）は、文脈では空白に置き換えます。
+func KWIC(corpus *model.Corpus, pattern *regexp.Regexp, filter Filter, contextLength int) []KWICHit {
+	hits := []KWICHit{}
+
+	for _, speech := range Speeches(corpus, filter) {
+		for _, loc := range pattern.FindAllStringIndex(speech.Text, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+
+			left := []rune(kwicContext(speech.Text[:loc[0]]))
+			if len(left) > contextLength {
+				left = left[len(left)-contextLength:]
+			}
+			right := []rune(kwicContext(speech.Text[loc[1]:]))
+			if len(right) > contextLength {
+				right = right[:contextLength]
+			}
+
+			hits = append(hits, KWICHit{
+				MeetingID:      speech.MeetingID,
+				WorkingGroupID: speech.WorkingGroupID,
+				Date:           speech.Date,
+				Turn:           speech.Turn,
+				SpeakerLabel:   speech.SpeakerLabel,
+				PersonID:       speech.PersonID,
+				Left:           string(left),
+				Keyword:        kwicContext(speech.Text[loc[0]:loc[1]]),
+				Right:          string(right),
+			})
+		}
+	}
+
+	return hits
+}
+
+// kwicContext は、文脈に含まれる改行などの空白文字を半角空白に置き換える関数です。
+func kwicContext(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,330 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// LDA の結果を書き出すファイルの名前です。
+const (
+	TopicWordsFileName    = "topic_words.csv"
+	SpeechTopicsFileName  = "speech_topics.csv"
+	MeetingTopicsFileName = "meeting_topics.csv"
+	PersonTopicsFileName  = "person_topics.csv"
+)
+
+// LDAOptions は、トピックモデル（LDA）の推定の条件を表す構造体です。
+type LDAOptions struct {
+	// K は、トピックの数です。
+	K int
+	// Iterations は、ギブスサンプリングの反復回数です。
+	Iterations int
+	// Alpha と Beta は、文書ごとのトピック分布とトピックごとの語の分布のディリクレ事前分布のパラメータです。
+	// 0 以下の場合は、Alpha = 50 / K、Beta = 0.1 とします。
+	Alpha float64
+	Beta  float64
+	// Seed は、乱数の種です。同じデータと条件であれば、同じ結果になります。
+	Seed int64
+	// Terms は、発言から語を取り出す条件です。GroupBy は使いません。
+	Terms TermOptions
+}
+
+// ldaDocument は、LDA の文書（発言1件）を表す構造体です。
+type ldaDocument struct {
+	Speech
+	words  []int
+	topics []int
+}
+
+// LDAModel は、推定したトピックモデルを表す構造体です。
+type LDAModel struct {
+	K          int
+	Alpha      float64
+	Beta       float64
+	Vocabulary []string
+
+	documents  []ldaDocument
+	docTopic   [][]int
+	topicWord  [][]int
+	topicTotal []int
+}
+
+// LDA は、filter の条件を満たす発言を文書として、崩壊型ギブスサンプリングで LDA を推定する関数です。
+// 語を単位とする場合は、発言に形態素解析の結果（Tokens）が必要です。語を含まない発言は文書に含めません。
+func LDA(corpus *model.Corpus, filter Filter, options LDAOptions) (*LDAModel, error) {
+	if options.K <= 0 {
+		return nil, errors.New("トピックの数には1以上の整数を指定してください。")
+	}
+	if options.Alpha <= 0 {
+		options.Alpha = 50 / float64(options.K)
+	}
+	if options.Beta <= 0 {
+		options.Beta = 0.1
+	}
+	if options.Terms.N <= 0 {
+		options.Terms.N = 1
+	}
+
+	lda := &LDAModel{K: options.K, Alpha: options.Alpha, Beta: options.Beta, Vocabulary: []string{}}
+	wordIDs := map[string]int{}
+	for _, speech := range Speeches(corpus, filter) {
+		document := ldaDocument{Speech: speech}
+		for _, term := range speechTerms(speech, options.Terms) {
+			id, exists := wordIDs[term]
+			if !exists {
+				id = len(lda.Vocabulary)
+				wordIDs[term] = id
+				lda.Vocabulary = append(lda.Vocabulary, term)
+			}
+			document.words = append(document.words, id)
+		}
+		if len(document.words) > 0 {
+			lda.documents = append(lda.documents, document)
+		}
+	}
+	if len(lda.documents) <= 0 {
+		return nil, errors.New("語を含む発言がありません。")
+	}
+
+	random := rand.New(rand.NewSource(options.Seed))
+	lda.docTopic = make([][]int, len(lda.documents))
+	lda.topicWord = make([][]int, lda.K)
+	for k := range lda.topicWord {
+		lda.topicWord[k] = make([]int, len(lda.Vocabulary))
+	}
+	lda.topicTotal = make([]int, lda.K)
+
+	// 語にトピックを無作為に割り当てる
+	for d := range lda.documents {
+		document := &lda.documents[d]
+		lda.docTopic[d] = make([]int, lda.K)
+		document.topics = make([]int, len(document.words))
+		for i, w := range document.words {
+			k := random.Intn(lda.K)
+			document.topics[i] = k
+			lda.docTopic[d][k]++
+			lda.topicWord[k][w]++
+			lda.topicTotal[k]++
+		}
+	}
+
+	vBeta := float64(len(lda.Vocabulary)) * lda.Beta
+	probabilities := make([]float64, lda.K)
+	for iteration := 0; iteration < options.Iterations; iteration++ {
+		for d := range lda.documents {
+			document := &lda.documents[d]
+			for i, w := range document.words {
+				k := document.topics[i]
+				lda.docTopic[d][k]--
+				lda.topicWord[k][w]--
+				lda.topicTotal[k]--
+
+				// 他の語の割り当てを条件としたトピックの確率に比例して、新しいトピックを選ぶ
+				total := 0.0
+				for t := 0; t < lda.K; t++ {
+					total += (float64(lda.docTopic[d][t]) + lda.Alpha) * (float64(lda.topicWord[t][w]) + lda.Beta) / (float64(lda.topicTotal[t]) + vBeta)
+					probabilities[t] = total
+				}
+				u := random.Float64() * total
+				k = sort.SearchFloat64s(probabilities, u)
+				if k >= lda.K {
+					k = lda.K - 1
+				}
+
+				document.topics[i] = k
+				lda.docTopic[d][k]++
+				lda.topicWord[k][w]++
+				lda.topicTotal[k]++
+			}
+		}
+	}
+
+	return lda, nil
+}
+
+// TopicWord は、トピックの上位の語とその確率を表す構造体です。
+type TopicWord struct {
+	Topic       int
+	Rank        int
+	Term        string
+	Probability float64
+}
+
+// TopicWords は、トピックごとに、確率の高い順に top 語を返すメソッドです。
+func (lda *LDAModel) TopicWords(top int) []TopicWord {
+	words := []TopicWord{}
+	vBeta := float64(len(lda.Vocabulary)) * lda.Beta
+
+	for k := 0; k < lda.K; k++ {
+		ids := make([]int, len(lda.Vocabulary))
+		for w := range ids {
+			ids[w] = w
+		}
+		sort.SliceStable(ids, func(i, j int) bool { return lda.topicWord[k][ids[i]] > lda.topicWord[k][ids[j]] })
+		if top > 0 && len(ids) > top {
+			ids = ids[:top]
+		}
+
+		for rank, w := range ids {
+			words = append(words, TopicWord{
+				Topic:       k,
+				Rank:        rank + 1,
+				Term:        lda.Vocabulary[w],
+				Probability: (float64(lda.topicWord[k][w]) + lda.Beta) / (float64(lda.topicTotal[k]) + vBeta),
+			})
+		}
+	}
+
+	return words
+}
+
+// TopicMixture は、発言・会議・人物ごとのトピックの混合比を表す構造体です。
+type TopicMixture struct {
+	Key    []string
+	Words  int
+	Topics []float64
+}
+
+// mixture は、トピックごとの語の数から、事前分布で平滑化した混合比を求めるメソッドです。
+func (lda *LDAModel) mixture(counts []int, words int) []float64 {
+	topics := make([]float64, lda.K)
+	for k := range topics {
+		topics[k] = (float64(counts[k]) + lda.Alpha) / (float64(words) + float64(lda.K)*lda.Alpha)
+	}
+	return topics
+}
+
+// groupMixtures は、文書を key で集計した混合比を、最初に現れた順に返すメソッドです。
+func (lda *LDAModel) groupMixtures(key func(document ldaDocument) []string) []TopicMixture {
+	counts := map[string][]int{}
+	words := map[string]int{}
+	keys := map[string][]string{}
+	order := []string{}
+
+	for d, document := range lda.documents {
+		k := key(document)
+		id := mixtureKey(k)
+		if _, exists := counts[id]; !exists {
+			counts[id] = make([]int, lda.K)
+			keys[id] = k
+			order = append(order, id)
+		}
+		for t, count := range lda.docTopic[d] {
+			counts[id][t] += count
+		}
+		words[id] += len(document.words)
+	}
+
+	mixtures := []TopicMixture{}
+	for _, id := range order {
+		mixtures = append(mixtures, TopicMixture{Key: keys[id], Words: words[id], Topics: lda.mixture(counts[id], words[id])})
+	}
+
+	return mixtures
+}
+
+// mixtureKey は、複数の値からなるキーをひとつの文字列にする関数です。
+func mixtureKey(values []string) string {
+	key := ""
+	for _, value := range values {
+		key += strconv.Quote(value)
+	}
+	return key
+}
+
+// SpeechTopics は、発言ごとのトピックの混合比を返すメソッドです。キーは会議ID・発言番号・人物ID・話者ラベルです。
+func (lda *LDAModel) SpeechTopics() []TopicMixture {
+	return lda.groupMixtures(func(document ldaDocument) []string {
+		return []string{document.MeetingID, strconv.Itoa(document.Turn), document.PersonID, document.SpeakerLabel}
+	})
+}
+
+// MeetingTopics は、会議ごとのトピックの混合比を返すメソッドです。キーは会議ID・ワーキンググループID・開催日です。
+func (lda *LDAModel) MeetingTopics() []TopicMixture {
+	return lda.groupMixtures(func(document ldaDocument) []string {
+		return []string{document.MeetingID, document.WorkingGroupID, document.Date}
+	})
+}
+
+// PersonTopics は、人物ごとのトピックの混合比を返すメソッドです。キーは人物ID・氏名です。
+// 名寄せされていない話者は、人物IDを空にして話者ラベルごとに集計します。
+func (lda *LDAModel) PersonTopics() []TopicMixture {
+	mixtures := lda.groupMixtures(func(document ldaDocument) []string {
+		return []string{document.PersonID, document.SpeakerName()}
+	})
+	// 名寄せ済みの人物をID順に並べ、その後に名寄せされていない話者を並べる
+	sort.SliceStable(mixtures, func(i, j int) bool {
+		resolvedI, resolvedJ := len(mixtures[i].Key[0]) > 0, len(mixtures[j].Key[0]) > 0
+		if resolvedI != resolvedJ {
+			return resolvedI
+		}
+		if mixtures[i].Key[0] != mixtures[j].Key[0] {
+			return mixtures[i].Key[0] < mixtures[j].Key[0]
+		}
+		return mixtures[i].Key[1] < mixtures[j].Key[1]
+	})
+	return mixtures
+}
+
+// ExportCSV は、トピックごとの上位の語と、発言・会議・人物ごとのトピックの混合比を outputdir に CSV で書き出すメソッドです。
+func (lda *LDAModel) ExportCSV(outputdir string, topWords int) error {
+	rows := [][]string{{"topic", "rank", "term", "probability"}}
+	for _, word := range lda.TopicWords(topWords) {
+		rows = append(rows, []string{strconv.Itoa(word.Topic), strconv.Itoa(word.Rank), word.Term, strconv.FormatFloat(word.Probability, 'f', 6, 64)})
+	}
+	if err := writeCSVFile(filepath.Join(outputdir, TopicWordsFileName), rows); err != nil {
+		return err
+	}
+
+	for _, file := range []struct {
+		name     string
+		header   []string
+		mixtures []TopicMixture
+	}{
+		{SpeechTopicsFileName, []string{"meeting_id", "turn", "person_id", "speaker_label"}, lda.SpeechTopics()},
+		{MeetingTopicsFileName, []string{"meeting_id", "wg_id", "date"}, lda.MeetingTopics()},
+		{PersonTopicsFileName, []string{"person_id", "name"}, lda.PersonTopics()},
+	} {
+		header := append(append([]string{}, file.header...), "words")
+		for k := 0; k < lda.K; k++ {
+			header = append(header, "topic_"+strconv.Itoa(k))
+		}
+
+		rows := [][]string{header}
+		for _, mixture := range file.mixtures {
+			row := append(append([]string{}, mixture.Key...), strconv.Itoa(mixture.Words))
+			for _, p := range mixture.Topics {
+				row = append(row, strconv.FormatFloat(p, 'f', 6, 64))
+			}
+			rows = append(rows, row)
+		}
+		if err := writeCSVFile(filepath.Join(outputdir, file.name), rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCSVFile は、行の配列を UTF-8 の CSV ファイルとして書き出す関数です。
+func writeCSVFile(filePath string, rows [][]string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,354 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// ネットワークの種類です。
+const (
+	// NetworkTurn は、発言の順番（誰の発言の次に誰が発言したか）を重み付きの有向グラフとして表します。
+	NetworkTurn = "turn"
+	// NetworkCoAttendance は、同じ会議で発言したことを重み付きの無向グラフとして表します。
+	NetworkCoAttendance = "coattendance"
+)
+
+// ネットワークの書き出し形式です。
+const (
+	GraphFormatGraphML = "graphml"
+	GraphFormatGEXF    = "gexf"
+	GraphFormatCSV     = "csv"
+)
+
+// GraphNode は、ネットワークの頂点（話者）を表す構造体です。
+type GraphNode struct {
+	ID        string
+	Label     string
+	RoleClass string
+	Speeches  int
+	Meetings  int
+}
+
+// GraphEdge は、ネットワークの辺を表す構造体です。Weight は、発言の順番の回数または同じ会議で発言した回数です。
+type GraphEdge struct {
+	Source string
+	Target string
+	Weight int
+}
+
+// Graph は、話者のネットワークを表す構造体です。
+type Graph struct {
+	Directed bool
+	Nodes    []GraphNode
+	Edges    []GraphEdge
+}
+
+// graphBuilder は、頂点と辺を集計しながら Graph を作成する構造体です。
+type graphBuilder struct {
+	nodes    map[string]*GraphNode
+	meetings map[string]map[string]bool
+	edges    map[[2]string]int
+}
+
+// node は、発言の話者に対応する頂点を返すメソッドです。includeUnresolved が false の場合、名寄せされていない話者は頂点にしません。
+func (builder *graphBuilder) node(speech Speech, includeUnresolved bool) *GraphNode {
+	if len(speech.PersonID) <= 0 && !includeUnresolved {
+		return nil
+	}
+
+	key := speech.SpeakerKey()
+	node, exists := builder.nodes[key]
+	if !exists {
+		node = &GraphNode{ID: key, Label: speech.SpeakerName(), RoleClass: speech.RoleClass}
+		builder.nodes[key] = node
+		builder.meetings[key] = map[string]bool{}
+	}
+	node.Speeches++
+	builder.meetings[key][speech.MeetingID] = true
+
+	return node
+}
+
+// graph は、集計した頂点と辺を ID 順に並べた Graph を返すメソッドです。
+func (builder *graphBuilder) graph(directed bool) *Graph {
+	graph := &Graph{Directed: directed, Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+
+	for key, node := range builder.nodes {
+		node.Meetings = len(builder.meetings[key])
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+
+	for pair, weight := range builder.edges {
+		graph.Edges = append(graph.Edges, GraphEdge{Source: pair[0], Target: pair[1], Weight: weight})
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Source != graph.Edges[j].Source {
+			return graph.Edges[i].Source < graph.Edges[j].Source
+		}
+		return graph.Edges[i].Target < graph.Edges[j].Target
+	})
+
+	return graph
+}
+
+// Network は、filter の条件を満たす会議の発言から、話者のネットワークを作成する関数です。
+// 頂点は名寄せ済みの人物（人物ID）で、includeUnresolved が true の場合は名寄せされていない話者（話者ラベル）も含めます。
+// turn では、同じ会議で続けて発言した2人の間に、前の話者から次の話者への辺を引きます。同じ話者が続く場合と、間に頂点にならない話者がいる場合は数えません。
+// coattendance では、同じ会議で発言した2人の間に辺を引きます。
+// 発言の順番を保つため、filter のうち話者の条件は使いません。
+func Network(corpus *model.Corpus, networkType string, filter Filter, includeUnresolved bool) (*Graph, error) {
+	if networkType != NetworkTurn && networkType != NetworkCoAttendance {
+		return nil, errors.New("ネットワークの種類 " + networkType + " には対応していません。")
+	}
+	filter.Speaker, filter.RoleClass = "", ""
+
+	builder := &graphBuilder{nodes: map[string]*GraphNode{}, meetings: map[string]map[string]bool{}, edges: map[[2]string]int{}}
+	var previous *GraphNode
+	previousMeetingID := ""
+	attendees := map[string]map[string]bool{}
+	meetingIDs := []string{}
+
+	for _, speech := range Speeches(corpus, filter) {
+		if speech.MeetingID != previousMeetingID {
+			previous, previousMeetingID = nil, speech.MeetingID
+			attendees[speech.MeetingID] = map[string]bool{}
+			meetingIDs = append(meetingIDs, speech.MeetingID)
+		}
+
+		node := builder.node(speech, includeUnresolved)
+		if node == nil {
+			previous = nil
+			continue
+		}
+		attendees[speech.MeetingID][node.ID] = true
+
+		if networkType == NetworkTurn && previous != nil && previous.ID != node.ID {
+			builder.edges[[2]string{previous.ID, node.ID}]++
+		}
+		previous = node
+	}
+
+	if networkType == NetworkCoAttendance {
+		for _, meetingID := range meetingIDs {
+			ids := []string{}
+			for id := range attendees[meetingID] {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for i := range ids {
+				for j := i + 1; j < len(ids); j++ {
+					builder.edges[[2]string{ids[i], ids[j]}]++
+				}
+			}
+		}
+	}
+
+	return builder.graph(networkType == NetworkTurn), nil
+}
+
+// Write は、Graph を format（graphml, gexf, csv）の形式で書き出すメソッドです。csv では辺のリストを書き出します。
+func (graph *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case GraphFormatGraphML:
+		return graph.writeGraphML(w)
+	case GraphFormatGEXF:
+		return graph.writeGEXF(w)
+	case GraphFormatCSV:
+		return graph.writeCSV(w)
+	}
+
+	return errors.New("出力形式 " + format + " には対応していません。")
+}
+
+// writeCSV は、辺のリストを CSV で書き出すメソッドです。
+func (graph *Graph) writeCSV(w io.Writer) error {
+	labels := map[string]string{}
+	for _, node := range graph.Nodes {
+		labels[node.ID] = node.Label
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"source", "target", "weight", "source_label", "target_label"})
+	for _, edge := range graph.Edges {
+		writer.Write([]string{edge.Source, edge.Target, strconv.Itoa(edge.Weight), labels[edge.Source], labels[edge.Target]})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// graphMLKey は、GraphML の属性の定義です。
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+// graphMLData は、GraphML の属性の値です。
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode は、GraphML の頂点です。
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge は、GraphML の辺です。
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLDocument は、GraphML の文書です。
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// writeGraphML は、GraphML 形式で書き出すメソッドです。
+func (graph *Graph) writeGraphML(w io.Writer) error {
+	doc := graphMLDocument{Xmlns: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []graphMLKey{
+		{ID: "label", For: "node", Name: "label", Type: "string"},
+		{ID: "role_class", For: "node", Name: "role_class", Type: "string"},
+		{ID: "speeches", For: "node", Name: "speeches", Type: "int"},
+		{ID: "meetings", For: "node", Name: "meetings", Type: "int"},
+		{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+	}
+	doc.Graph.ID = "G"
+	doc.Graph.EdgeDefault = "undirected"
+	if graph.Directed {
+		doc.Graph.EdgeDefault = "directed"
+	}
+
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{
+			{Key: "label", Value: node.Label},
+			{Key: "role_class", Value: node.RoleClass},
+			{Key: "speeches", Value: strconv.Itoa(node.Speeches)},
+			{Key: "meetings", Value: strconv.Itoa(node.Meetings)},
+		}})
+	}
+	for i, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{ID: "e" + strconv.Itoa(i), Source: edge.Source, Target: edge.Target, Data: []graphMLData{
+			{Key: "weight", Value: strconv.Itoa(edge.Weight)},
+		}})
+	}
+
+	return writeXML(w, doc)
+}
+
+// gexfAttribute は、GEXF の属性の定義です。
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+// gexfAttValue は、GEXF の属性の値です。
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfNode は、GEXF の頂点です。
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+// gexfEdge は、GEXF の辺です。
+type gexfEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Weight int    `xml:"weight,attr"`
+}
+
+// gexfDocument は、GEXF 1.3 の文書です。
+type gexfDocument struct {
+	XMLName xml.Name `xml:"gexf"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Meta    struct {
+		Creator     string `xml:"creator"`
+		Description string `xml:"description"`
+	} `xml:"meta"`
+	Graph struct {
+		DefaultEdgeType string `xml:"defaultedgetype,attr"`
+		Attributes      struct {
+			Class      string          `xml:"class,attr"`
+			Attributes []gexfAttribute `xml:"attribute"`
+		} `xml:"attributes"`
+		Nodes []gexfNode `xml:"nodes>node"`
+		Edges []gexfEdge `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+// writeGEXF は、GEXF 1.3 形式で書き出すメソッドです。
+func (graph *Graph) writeGEXF(w io.Writer) error {
+	doc := gexfDocument{Xmlns: "http://gexf.net/1.3", Version: "1.3"}
+	doc.Meta.Creator = "meroku"
+	doc.Meta.Description = "turn-taking network"
+	doc.Graph.DefaultEdgeType = "directed"
+	if !graph.Directed {
+		doc.Meta.Description = "co-attendance network"
+		doc.Graph.DefaultEdgeType = "undirected"
+	}
+	doc.Graph.Attributes.Class = "node"
+	doc.Graph.Attributes.Attributes = []gexfAttribute{
+		{ID: "role_class", Title: "role_class", Type: "string"},
+		{ID: "speeches", Title: "speeches", Type: "integer"},
+		{ID: "meetings", Title: "meetings", Type: "integer"},
+	}
+
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{ID: node.ID, Label: node.Label, AttValues: []gexfAttValue{
+			{For: "role_class", Value: node.RoleClass},
+			{For: "speeches", Value: strconv.Itoa(node.Speeches)},
+			{For: "meetings", Value: strconv.Itoa(node.Meetings)},
+		}})
+	}
+	for i, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{ID: "e" + strconv.Itoa(i), Source: edge.Source, Target: edge.Target, Weight: edge.Weight})
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML は、XML 宣言に続けて値をインデント付きの XML として書き出す関数です。
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,68 @@
+package analysis
+
+import (
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// Speech は、分析の対象とする発言1件を、議事録・話者とあわせて表す構造体です。
+type Speech struct {
+	model.SpeechRecord
+	Minutes *model.Minutes
+	Speach  *model.Speach
+}
+
+// SpeakerKey は、話者を区別するためのキーを返すメソッドです。名寄せ済みの話者は人物ID、それ以外は話者ラベルを返します。
+func (speech Speech) SpeakerKey() string {
+	if len(speech.PersonID) > 0 {
+		return speech.PersonID
+	}
+	return speech.SpeakerLabel
+}
+
+// SpeakerName は、話者の表示名を返すメソッドです。名寄せ済みの話者は氏名、それ以外は話者ラベルを返します。
+func (speech Speech) SpeakerName() string {
+	if speech.Speach.Speaker != nil && len(speech.Speach.Speaker.Person.Name) > 0 {
+		return speech.Speach.Speaker.Person.Name
+	}
+	return speech.SpeakerLabel
+}
+
+// Speeches は、Corpus の発言のうち filter の条件を満たすものを、会議・発言番号の順に返す関数です。
+// 同じ会議IDの議事録が複数ある場合は、最初のものだけを対象にします。
+func Speeches(corpus *model.Corpus, filter Filter) []Speech {
+	speeches := []Speech{}
+	meetingIDs := map[string]bool{}
+
+	for i := range corpus.Minutes {
+		minutes := &corpus.Minutes[i]
+		if meetingIDs[minutes.ID] || !filter.MatchMinutes(minutes) {
+			continue
+		}
+		meetingIDs[minutes.ID] = true
+
+		speaches := map[int]*model.Speach{}
+		for _, speach := range minutes.Speaches {
+			if speach != nil {
+				speaches[speach.Turn] = speach
+			}
+		}
+
+		for _, record := range minutes.SpeechRecords() {
+			speach := speaches[record.Turn]
+			if !filter.MatchSpeaker(speach.Speaker) || (filter.AgendaItems != nil && !filter.AgendaItems[record.AgendaItem]) {
+				continue
+			}
+			speeches = append(speeches, Speech{SpeechRecord: record, Minutes: minutes, Speach: speach})
+		}
+	}
+
+	return speeches
+}
+
+// WorkingGroupName は、ワーキンググループの名称を返す関数です。一覧にない場合はIDを返します。
+func WorkingGroupName(corpus *model.Corpus, id string) string {
+	if wg, exists := corpus.WorkingGroups[id]; exists && len(wg.Name) > 0 {
+		return wg.Name
+	}
+	return id
+}
@@ -0,0 +1,201 @@
+package analysis
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// 集計の単位です。
+const (
+	GroupByWorkingGroup  = "wg"
+	GroupByMeeting       = "meeting"
+	GroupByPerson        = "person"
+	GroupByRole          = "role"
+	GroupByMeetingPerson = "meeting-person"
+	GroupByYear          = "year"
+	GroupByMonth         = "month"
+	GroupByAll           = "all"
+	GroupByAgenda        = "agenda"
+)
+
+// StatsGroups は、Stats で指定できる集計の単位の一覧です。
+var StatsGroups = []string{GroupByWorkingGroup, GroupByMeeting, GroupByPerson, GroupByRole, GroupByMeetingPerson, GroupByYear, GroupByMonth, GroupByAgenda}
+
+// StatsRow は、集計の単位ごとの発言の統計を表す構造体です。
+// 割合（Share）は、meeting-person と agenda では会議全体に対する割合（話者などの条件で絞り込んだ場合も、会議のすべての発言を分母とします）、
+// それ以外では対象とした発言全体に対する割合です。
+type StatsRow struct {
+	Key                   string
+	Label                 string
+	MeetingID             string `json:",omitempty"`
+	Meetings              int
+	Speakers              int
+	Speeches              int
+	Characters            int
+	Sentences             int
+	SpeechShare           float64
+	CharacterShare        float64
+	MemberCharacters      int
+	SecretariatCharacters int
+	// MemberRatio は、委員と事務局の発言文字数の合計に対する委員の発言文字数の割合です。
+	MemberRatio float64
+}
+
+// StatsHeader は、StatsRow を表として書き出す際の列名です。
+var StatsHeader = []string{"key", "label", "meetings", "speakers", "speeches", "characters", "sentences", "speech_share", "character_share", "member_characters", "secretariat_characters", "member_ratio"}
+
+// Strings は、StatsRow を表の1行分の文字列の配列に変換するメソッドです。
+func (row StatsRow) Strings() []string {
+	return []string{
+		row.Key,
+		row.Label,
+		strconv.Itoa(row.Meetings),
+		strconv.Itoa(row.Speakers),
+		strconv.Itoa(row.Speeches),
+		strconv.Itoa(row.Characters),
+		strconv.Itoa(row.Sentences),
+		strconv.FormatFloat(row.SpeechShare, 'f', 4, 64),
+		strconv.FormatFloat(row.CharacterShare, 'f', 4, 64),
+		strconv.Itoa(row.MemberCharacters),
+		strconv.Itoa(row.SecretariatCharacters),
+		strconv.FormatFloat(row.MemberRatio, 'f', 4, 64),
+	}
+}
+
+// statsAccumulator は、集計中の値を保持する構造体です。
+type statsAccumulator struct {
+	row      StatsRow
+	parent   string
+	meetings map[string]bool
+	speakers map[string]bool
+}
+
+// Stats は、Corpus の発言を groupBy で指定した単位ごとに集計する関数です。
+// 名寄せ済みの話者は人物IDで、名寄せされていない話者は話者ラベルで区別します。
+func Stats(corpus *model.Corpus, groupBy string, filter Filter) ([]StatsRow, error) {
+	valid := false
+	for _, group := range StatsGroups {
+		valid = valid || group == groupBy
+	}
+	if !valid {
+		return nil, errors.New("集計の単位 " + groupBy + " には対応していません。")
+	}
+
+	accumulators := map[string]*statsAccumulator{}
+	order := []string{}
+	totalSpeeches := map[string]int{}
+	totalCharacters := map[string]int{}
+
+	for _, speech := range Speeches(corpus, filter) {
+		key, label := speechGroup(corpus, speech, groupBy)
+		parent := ""
+		if groupBy == GroupByMeetingPerson || groupBy == GroupByAgenda {
+			parent = speech.MeetingID
+		}
+
+		acc, exists := accumulators[key]
+		if !exists {
+			acc = &statsAccumulator{row: StatsRow{Key: key, Label: label}, parent: parent, meetings: map[string]bool{}, speakers: map[string]bool{}}
+			if groupBy == GroupByMeetingPerson || groupBy == GroupByAgenda {
+				acc.row.MeetingID = speech.MeetingID
+			}
+			accumulators[key] = acc
+			order = append(order, key)
+		}
+
+		characters := speech.CharCount
+		acc.meetings[speech.MeetingID] = true
+		acc.speakers[speech.SpeakerKey()] = true
+		acc.row.Speeches++
+		acc.row.Characters += characters
+		acc.row.Sentences += speech.SentenceCount
+		switch speech.RoleClass {
+		case model.RoleClassMember:
+			acc.row.MemberCharacters += characters
+		case model.RoleClassSecretariat:
+			acc.row.SecretariatCharacters += characters
+		}
+
+		if len(parent) <= 0 {
+			totalSpeeches[parent]++
+			totalCharacters[parent] += characters
+		} else if _, exists := totalSpeeches[parent]; !exists {
+			// 会議全体に対する割合とするため、話者の条件によらず会議のすべての発言を数える
+			for _, record := range speech.Minutes.SpeechRecords() {
+				totalSpeeches[parent]++
+				totalCharacters[parent] += record.CharCount
+			}
+		}
+	}
+
+	rows := []StatsRow{}
+	for _, key := range order {
+		acc := accumulators[key]
+		row := acc.row
+		row.Meetings = len(acc.meetings)
+		row.Speakers = len(acc.speakers)
+		row.SpeechShare = ratio(row.Speeches, totalSpeeches[acc.parent])
+		row.CharacterShare = ratio(row.Characters, totalCharacters[acc.parent])
+		row.MemberRatio = ratio(row.MemberCharacters, row.MemberCharacters+row.SecretariatCharacters)
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		switch groupBy {
+		case GroupByPerson, GroupByRole:
+			return rows[i].Characters > rows[j].Characters
+		case GroupByMeetingPerson:
+			if rows[i].MeetingID != rows[j].MeetingID {
+				return rows[i].MeetingID < rows[j].MeetingID
+			}
+			return rows[i].Characters > rows[j].Characters
+		case GroupByAgenda:
+			// 会議の中では議題の順（現れた順）に並べる
+			return rows[i].MeetingID < rows[j].MeetingID
+		}
+		return rows[i].Key < rows[j].Key
+	})
+
+	return rows, nil
+}
+
+// speechGroup は、発言が属する集計の単位のキーと表示名を返す関数です。
+func speechGroup(corpus *model.Corpus, speech Speech, groupBy string) (key string, label string) {
+	switch groupBy {
+	case GroupByWorkingGroup:
+		return speech.WorkingGroupID, WorkingGroupName(corpus, speech.WorkingGroupID)
+	case GroupByMeeting:
+		return speech.MeetingID, speech.Minutes.Title
+	case GroupByPerson:
+		return speech.SpeakerKey(), speech.SpeakerName()
+	case GroupByRole:
+		return speech.RoleClass, speech.RoleClass
+	case GroupByMeetingPerson:
+		return speech.MeetingID + "/" + speech.SpeakerKey(), speech.SpeakerName()
+	case GroupByYear:
+		if len(speech.Date) >= 4 {
+			return speech.Date[:4], speech.Date[:4]
+		}
+	case GroupByMonth:
+		if len(speech.Date) >= 7 {
+			return speech.Date[:7], speech.Date[:7]
+		}
+	case GroupByAll:
+		return GroupByAll, GroupByAll
+	case GroupByAgenda:
+		return speech.MeetingID + "/" + strconv.Itoa(speech.AgendaItem), speech.Minutes.AgendaTitle(speech.AgendaItem)
+	}
+
+	return "unknown", "unknown"
+}
+
+// ratio は、分母が 0 の場合は 0 を返す割り算の関数です。
+func ratio(numerator int, denominator int) float64 {
+	if denominator <= 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// DisplayWidth は、端末に表示したときの文字列の幅を返す関数です。全角文字は2、それ以外は1として数えます。
+func DisplayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+
+	return w
+}
+
+// PadRight は、表示幅が n になるまで文字列の右側を空白で埋める関数です。
+func PadRight(s string, n int) string {
+	if w := DisplayWidth(s); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
+
+// PadLeft は、表示幅が n になるまで文字列の左側を空白で埋める関数です。
+func PadLeft(s string, n int) string {
+	if w := DisplayWidth(s); w < n {
+		return strings.Repeat(" ", n-w) + s
+	}
+	return s
+}
+
+// WriteTable は、見出しと行を、全角文字の幅を考慮して列をそろえた表として書き出す関数です。
+// rightAligned に含まれる列（数値など）は右寄せにします。
+func WriteTable(w io.Writer, header []string, rows [][]string, rightAligned map[int]bool) error {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i < len(widths) && DisplayWidth(cell) > widths[i] {
+				widths[i] = DisplayWidth(cell)
+			}
+		}
+	}
+
+	for _, row := range append([][]string{header}, rows...) {
+		cells := []string{}
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			if rightAligned[i] {
+				cells = append(cells, PadLeft(cell, widths[i]))
+			} else if i == len(row)-1 {
+				cells = append(cells, cell)
+			} else {
+				cells = append(cells, PadRight(cell, widths[i]))
+			}
+		}
+		if _, err := io.WriteString(w, strings.Join(cells, "  ")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+package analysis
+
+import (
+	"bufio"
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// 語の単位です。
+const (
+	// TermUnitToken は、形態素解析で得られた語（原形）を単位にします。
+	TermUnitToken = "token"
+	// TermUnitChar は、文字を単位にします。
+	TermUnitChar = "char"
+)
+
+// 語の並べ替えの基準です。
+const (
+	TermMeasureCount = "count"
+	TermMeasureTFIDF = "tfidf"
+)
+
+// TermsGroups は、Terms で指定できる集計の単位の一覧です。
+var TermsGroups = []string{GroupByAll, GroupByWorkingGroup, GroupByMeeting, GroupByPerson, GroupByRole, GroupByYear, GroupByMonth, GroupByAgenda}
+
+// DefaultTermPOS は、語を単位とする場合に既定で数える品詞（前方一致）です。
+var DefaultTermPOS = []string{"名詞", "動詞-自立", "形容詞-自立"}
+
+// excludedTermPOS は、DefaultTermPOS に含まれていても数えない品詞（前方一致）です。
+var excludedTermPOS = []string{"名詞-数", "名詞-非自立", "名詞-代名詞", "名詞-特殊"}
+
+// DefaultStopwords は、議事録に頻出し、内容の違いを表さない語です。
+var DefaultStopwords = []string{
+	"する", "いる", "ある", "なる", "れる", "られる", "できる", "思う", "いう", "言う", "おる", "いただく", "くださる", "申し上げる", "存ずる",
+	"こと", "もの", "ところ", "とき", "ため", "よう", "の", "ん", "さ",
+	"それ", "これ", "あれ", "そこ", "ここ", "今", "方", "皆様", "先生",
+	"お願い", "ありがとう", "御", "ご", "委員", "事務局", "説明",
+}
+
+// TermOptions は、語の集計の条件を表す構造体です。
+type TermOptions struct {
+	// GroupBy は、集計の単位（TermsGroups のいずれか）です。
+	GroupBy string
+	// Unit は、語の単位（token, char）です。
+	Unit string
+	// N は、n-gram の n です。1 の場合は語（文字）をそのまま数えます。
+	N int
+	// POS は、語を単位とする場合に数える品詞（前方一致）です。空の場合は記号以外のすべての品詞を数えます。
+	POS []string
+	// Stopwords は、数えない語の集合です。語を単位とする n-gram では、ストップワードを含むものも数えません。
+	Stopwords map[string]bool
+	// MinCount は、集計の単位ごとの出現回数がこれより少ない語を結果に含めない閾値です。
+	MinCount int
+}
+
+// TermRow は、集計の単位ごとの語1つ分の統計を表す構造体です。
+type TermRow struct {
+	Group string
+	Label string
+	Term  string
+	Count int
+	// TF は、集計の単位に含まれる語の総数に対する出現回数の割合です。
+	TF float64
+	// DF は、その語が現れる集計の単位の数です。
+	DF    int
+	IDF   float64
+	TFIDF float64
+}
+
+// TermsHeader は、TermRow を表として書き出す際の列名です。
+var TermsHeader = []string{"group", "label", "term", "count", "tf", "df", "idf", "tfidf"}
+
+// Strings は、TermRow を表の1行分の文字列の配列に変換するメソッドです。
+func (row TermRow) Strings() []string {
+	return []string{
+		row.Group,
+		row.Label,
+		row.Term,
+		strconv.Itoa(row.Count),
+		strconv.FormatFloat(row.TF, 'f', 6, 64),
+		strconv.Itoa(row.DF),
+		strconv.FormatFloat(row.IDF, 'f', 6, 64),
+		strconv.FormatFloat(row.TFIDF, 'f', 6, 64),
+	}
+}
+
+// LoadStopwords は、1行に1語を記載したファイルからストップワードを読み込む関数です。空行と「#」で始まる行は無視します。
+func LoadStopwords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stopwords := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) <= 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		stopwords = append(stopwords, line)
+	}
+
+	return stopwords, scanner.Err()
+}
+
+// termSegments は、発言を n-gram を作る単位（語または文字）の列に分割する関数です。
+// 数えない語や記号の位置で列を区切るため、n-gram はそれらをまたぎません。
+func termSegments(speech Speech, options TermOptions) [][]string {
+	segments := [][]string{}
+	current := []string{}
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, current)
+			current = []string{}
+		}
+	}
+
+	switch options.Unit {
+	case TermUnitToken:
+		for _, token := range speech.Speach.Tokens {
+			if !matchTermPOS(token.POS, options.POS) {
+				flush()
+				continue
+			}
+			current = append(current, token.BaseForm)
+		}
+	case TermUnitChar:
+		for _, r := range speech.Text {
+			if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+				flush()
+				continue
+			}
+			current = append(current, string(r))
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// speechTerms は、発言に含まれる語（n-gram）を、出現順に返す関数です。ストップワードは含めません。
+func speechTerms(speech Speech, options TermOptions) []string {
+	separator := ""
+	if options.Unit == TermUnitToken {
+		separator = " "
+	}
+
+	terms := []string{}
+	for _, segment := range termSegments(speech, options) {
+	ngram:
+		for i := 0; i+options.N <= len(segment); i++ {
+			for _, unit := range segment[i : i+options.N] {
+				if options.Unit == TermUnitToken && options.Stopwords[unit] {
+					continue ngram
+				}
+			}
+			term := strings.Join(segment[i:i+options.N], separator)
+			if options.Stopwords[term] {
+				continue
+			}
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// matchTermPOS は、品詞が数える対象かを判定する関数です。
+func matchTermPOS(pos string, includes []string) bool {
+	hasPrefix := func(prefixes []string) bool {
+		for _, prefix := range prefixes {
+			if pos == prefix || strings.HasPrefix(pos, prefix+"-") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(includes) <= 0 {
+		return !strings.HasPrefix(pos, "記号")
+	}
+
+	return hasPrefix(includes) && !hasPrefix(excludedTermPOS)
+}
+
+// Terms は、filter の条件を満たす発言の語（n-gram）を、集計の単位ごとに数える関数です。
+// TF-IDF は、集計の単位をひとつの文書とみなし、TF × log(単位の数 / DF) で求めます。
+// 語を単位とする場合は、発言に形態素解析の結果（Tokens）が必要です。結果は、集計の単位ごとに出現回数の多い順に並べます。
+func Terms(corpus *model.Corpus, filter Filter, options TermOptions) ([]TermRow, error) {
+	valid := false
+	for _, group := range TermsGroups {
+		valid = valid || group == options.GroupBy
+	}
+	if !valid {
+		return nil, errors.New("集計の単位 " + options.GroupBy + " には対応していません。")
+	}
+	if options.Unit != TermUnitToken && options.Unit != TermUnitChar {
+		return nil, errors.New("語の単位 " + options.Unit + " には対応していません。")
+	}
+	if options.N <= 0 {
+		options.N = 1
+	}
+
+	counts := map[string]map[string]int{}
+	totals := map[string]int{}
+	labels := map[string]string{}
+	groups := []string{}
+
+	for _, speech := range Speeches(corpus, filter) {
+		key, label := speechGroup(corpus, speech, options.GroupBy)
+		if _, exists := counts[key]; !exists {
+			counts[key] = map[string]int{}
+			labels[key] = label
+			groups = append(groups, key)
+		}
+
+		for _, term := range speechTerms(speech, options) {
+			counts[key][term]++
+			totals[key]++
+		}
+	}
+	sort.Strings(groups)
+
+	df := map[string]int{}
+	for _, termCounts := range counts {
+		for term := range termCounts {
+			df[term]++
+		}
+	}
+
+	rows := []TermRow{}
+	for _, key := range groups {
+		groupRows := []TermRow{}
+		for term, count := range counts[key] {
+			if count < options.MinCount {
+				continue
+			}
+			row := TermRow{Group: key, Label: labels[key], Term: term, Count: count, DF: df[term]}
+			row.TF = ratio(count, totals[key])
+			row.IDF = math.Log(float64(len(groups)) / float64(row.DF))
+			row.TFIDF = row.TF * row.IDF
+			groupRows = append(groupRows, row)
+		}
+		sort.Slice(groupRows, func(i, j int) bool {
+			if groupRows[i].Count != groupRows[j].Count {
+				return groupRows[i].Count > groupRows[j].Count
+			}
+			return groupRows[i].Term < groupRows[j].Term
+		})
+		rows = append(rows, groupRows...)
+	}
+
+	return rows, nil
+}
+
+// TopTerms は、集計の単位ごとに measure（count, tfidf）の大きい順に並べ替え、上位 top 語に絞り込む関数です。top が 0 以下の場合は絞り込みません。
+func TopTerms(rows []TermRow, measure string, top int) ([]TermRow, error) {
+	if measure != TermMeasureCount && measure != TermMeasureTFIDF {
+		return nil, errors.New("並べ替えの基準 " + measure + " には対応していません。")
+	}
+
+	result := []TermRow{}
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].Group == rows[start].Group {
+			end++
+		}
+
+		groupRows := append([]TermRow{}, rows[start:end]...)
+		if measure == TermMeasureTFIDF {
+			sort.SliceStable(groupRows, func(i, j int) bool { return groupRows[i].TFIDF > groupRows[j].TFIDF })
+		}
+		if top > 0 && len(groupRows) > top {
+			groupRows = groupRows[:top]
+		}
+		result = append(result, groupRows...)
+		start = end
+	}
+
+	return result, nil
+}
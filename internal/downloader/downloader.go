@@ -1,6 +1,8 @@
 package downloader
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -11,8 +13,18 @@ import (
 	"time"
 )
 
-// Download is ...
-func Download(url string, path string) (bool, error) {
+// FetchRecord は、1つのファイルを取得した際の記録（取得元URL・保存先・取得日時・SHA-256）を表す構造体です。
+type FetchRecord struct {
+	URL       string
+	Path      string
+	FetchedAt time.Time
+	SHA256    string
+}
+
+// Fetch は、URLからファイルを取得して path に保存し、その取得記録を返す関数です。
+func Fetch(url string, path string) (FetchRecord, error) {
+	record := FetchRecord{URL: url, Path: path}
+
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		log.Fatal(err)
@@ -22,13 +34,14 @@ func Download(url string, path string) (bool, error) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode >= 400 {
 		err := errors.New(url + " : " + response.Status)
-		return false, err
+		return record, err
 	}
 
-	body, _ := ioutil.ReadAll(response.Body)
+	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -39,7 +52,21 @@ func Download(url string, path string) (bool, error) {
 	}
 	defer fp.Close()
 
-	fp.WriteString(string(body))
+	fp.Write(body)
+
+	sum := sha256.Sum256(body)
+	record.SHA256 = hex.EncodeToString(sum[:])
+	record.FetchedAt = time.Now()
+
+	return record, nil
+}
+
+// Download is ...
+func Download(url string, path string) (bool, error) {
+	_, err := Fetch(url, path)
+	if err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -48,6 +75,7 @@ func Download(url string, path string) (bool, error) {
 type DownloadReport struct {
 	DownloadedList []string
 	ErrorList      []string
+	Records        []FetchRecord
 }
 
 // ToJSON is ...
@@ -80,3 +108,28 @@ func (report DownloadReport) Save(datadir string) (bool, error) {
 
 	return true, nil
 }
+
+// LoadReports は、datadir に保存されたすべてのダウンロードレポートを読み込む関数です。
+func LoadReports(datadir string) ([]DownloadReport, error) {
+	reports := []DownloadReport{}
+
+	files, err := filepath.Glob(filepath.Join(datadir, "report_*.json"))
+	if err != nil {
+		return reports, err
+	}
+
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return reports, err
+		}
+
+		var report DownloadReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
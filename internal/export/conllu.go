@@ -0,0 +1,219 @@
+package export
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// conlluTerminators は、文末とみなす語です。
+const conlluTerminators = "。？！?!"
+
+// conlluClosingBrackets は、文末の直後にあれば前の文に含める閉じ括弧です。
+const conlluClosingBrackets = "」』）)"
+
+// upos は、IPA辞書の品詞（前方一致）と Universal Dependencies の品詞タグ（UPOS）の対応です。上から順に照合します。
+var upos = [][2]string{
+	{"名詞-固有名詞", "PROPN"},
+	{"名詞-代名詞", "PRON"},
+	{"名詞-数", "NUM"},
+	{"名詞", "NOUN"},
+	{"接頭詞", "NOUN"},
+	{"動詞-非自立", "AUX"},
+	{"動詞", "VERB"},
+	{"形容詞", "ADJ"},
+	{"副詞", "ADV"},
+	{"連体詞", "DET"},
+	{"接続詞", "CCONJ"},
+	{"助詞-接続助詞", "SCONJ"},
+	{"助詞-終助詞", "PART"},
+	{"助詞", "ADP"},
+	{"助動詞", "AUX"},
+	{"感動詞", "INTJ"},
+	{"フィラー", "INTJ"},
+	{"記号-一般", "SYM"},
+	{"記号-アルファベット", "SYM"},
+	{"記号", "PUNCT"},
+}
+
+// UPOS は、IPA辞書の品詞を Universal Dependencies の品詞タグに変換する関数です。対応するものがない場合は X を返します。
+func UPOS(pos string) string {
+	for _, pair := range upos {
+		if pos == pair[0] || strings.HasPrefix(pos, pair[0]+"-") {
+			return pair[1]
+		}
+	}
+
+	return "X"
+}
+
+// conlluToken は、CoNLL-U に書き出す語と、その直後に空白があったかを表す構造体です。
+type conlluToken struct {
+	model.Token
+	SpaceAfter bool
+}
+
+// conlluSentences は、発言の形態素解析の結果を文に分割する関数です。
+// 語を段落（Talks）と照合し、「。？！」の後と、完結した段落の終わりで文を区切ります。
+// 文の途中で終わっている段落は、ParseMinutesFromPDF2Html と同じ基準で次の段落とつなぎます。
+func conlluSentences(speach *model.Speach) [][]conlluToken {
+	sentences := [][]conlluToken{}
+	current := []conlluToken{}
+	flush := func() {
+		if len(current) > 0 {
+			sentences = append(sentences, current)
+			current = []conlluToken{}
+		}
+	}
+
+	talks := [][]rune{}
+	for _, talk := range speach.Talks {
+		talks = append(talks, []rune(talk))
+	}
+	talkIndex, offset := 0, 0
+	aligned := true
+
+	// skipSpaces は、照合位置を空白の後ろまで進め、段落の終わりに達した場合は次の段落に移る関数です。
+	// 完結した段落の終わりを越えた場合は true を返します。
+	skipSpaces := func() (boundary bool) {
+		for talkIndex < len(talks) {
+			for offset < len(talks[talkIndex]) && unicode.IsSpace(talks[talkIndex][offset]) {
+				offset++
+			}
+			if offset < len(talks[talkIndex]) {
+				return boundary
+			}
+			if !model.ContinuesOnNextLine(string(talks[talkIndex])) {
+				boundary = true
+			}
+			talkIndex, offset = talkIndex+1, 0
+		}
+		return boundary
+	}
+
+	endOfSentence := false
+	for i, token := range speach.Tokens {
+		if aligned {
+			if boundary := skipSpaces(); boundary && i > 0 {
+				flush()
+				endOfSentence = false
+			}
+			surface := []rune(token.Surface)
+			if talkIndex < len(talks) && offset+len(surface) <= len(talks[talkIndex]) && string(talks[talkIndex][offset:offset+len(surface)]) == token.Surface {
+				offset += len(surface)
+			} else {
+				// 段落と照合できない場合は、以降は文末記号だけで区切る
+				aligned = false
+			}
+		}
+
+		// 文末記号の後は、閉じ括弧と文末記号が続く間は同じ文に含める
+		if endOfSentence && strings.Trim(token.Surface, conlluClosingBrackets+conlluTerminators) != "" {
+			flush()
+			endOfSentence = false
+		}
+
+		spaceAfter := false
+		if aligned && talkIndex < len(talks) && offset < len(talks[talkIndex]) {
+			spaceAfter = unicode.IsSpace(talks[talkIndex][offset])
+		}
+		current = append(current, conlluToken{Token: token, SpaceAfter: spaceAfter})
+
+		if strings.Trim(token.Surface, conlluTerminators) == "" {
+			endOfSentence = true
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+// conlluValue は、CoNLL-U の列に書き出す値を返す関数です。空の値や空白を含む値は書き出せないため置き換えます。
+func conlluValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) <= 0 {
+		return "_"
+	}
+	return s
+}
+
+// CoNLLU は、形態素解析済みの発言を CoNLL-U 形式で filePath に書き出す関数です。
+// 1文を1ブロックとし、# sent_id・# meeting・# speaker・# text の注釈を付けます。依存構造の列は「_」とします。
+// 形態素解析の結果を持たない発言は書き出しません。
+func CoNLLU(corpus *model.Corpus, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	meetingIDs := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if meetingIDs[minutes.ID] {
+			continue
+		}
+		meetingIDs[minutes.ID] = true
+
+		newdoc := true
+		for _, speach := range minutes.Speaches {
+			if speach == nil || speach.Speaker == nil || len(speach.Tokens) <= 0 {
+				continue
+			}
+
+			for i, sentence := range conlluSentences(speach) {
+				if newdoc {
+					w.WriteString("# newdoc id = " + minutes.ID + "\n")
+					newdoc = false
+				}
+				w.WriteString("# sent_id = " + minutes.ID + "-" + strconv.Itoa(speach.Turn) + "-" + strconv.Itoa(i+1) + "\n")
+				w.WriteString("# meeting = " + minutes.ID + "\n")
+				w.WriteString("# speaker = " + speach.Speaker.Label + "\n")
+				if len(speach.Speaker.Person.ID) > 0 {
+					w.WriteString("# person_id = " + speach.Speaker.Person.ID + "\n")
+				}
+
+				text := ""
+				for _, token := range sentence {
+					text += token.Surface
+					if token.SpaceAfter {
+						text += " "
+					}
+				}
+				w.WriteString("# text = " + strings.TrimSpace(text) + "\n")
+
+				for j, token := range sentence {
+					misc := []string{}
+					if !token.SpaceAfter {
+						misc = append(misc, "SpaceAfter=No")
+					}
+					if len(token.Reading) > 0 {
+						misc = append(misc, "Reading="+conlluValue(token.Reading))
+					}
+
+					w.WriteString(strings.Join([]string{
+						strconv.Itoa(j + 1),
+						conlluValue(token.Surface),
+						conlluValue(token.BaseForm),
+						UPOS(token.POS),
+						conlluValue(token.POS),
+						"_", "_", "_", "_",
+						conlluValue(strings.Join(misc, "|")),
+					}, "\t") + "\n")
+				}
+				w.WriteString("\n")
+			}
+		}
+	}
+
+	return w.Flush()
+}
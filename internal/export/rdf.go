@@ -0,0 +1,370 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// RDF の書き出し形式です。
+const (
+	RDFTurtle   = "turtle"
+	RDFNTriples = "ntriples"
+)
+
+// DefaultRDFBase は、IRI の基底として使用する既定値です。公開する場合は実際のURLを指定してください。
+const DefaultRDFBase = "http://example.org/meroku/"
+
+// 使用する語彙の名前空間です。発言には LinkedEP の語彙（lpv）を使用します。
+const (
+	rdfNS     = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xsdNS     = "http://www.w3.org/2001/XMLSchema#"
+	schemaNS  = "https://schema.org/"
+	foafNS    = "http://xmlns.com/foaf/0.1/"
+	dctermsNS = "http://purl.org/dc/terms/"
+	lpvNS     = "http://purl.org/linkedpolitics/vocabulary/"
+)
+
+// rdfPrefixes は、Turtle で使用する接頭辞です。meroku 独自の語彙は基底IRIの vocab/ 以下に置きます。
+var rdfPrefixes = [][2]string{
+	{"rdf", rdfNS},
+	{"xsd", xsdNS},
+	{"schema", schemaNS},
+	{"foaf", foafNS},
+	{"dcterms", dctermsNS},
+	{"lpv", lpvNS},
+}
+
+// rdfLocalNamePattern は、接頭辞付きの名前で表記できる局所名です。
+var rdfLocalNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// rdfNode は、RDF の目的語（IRI またはリテラル）を表す構造体です。
+type rdfNode struct {
+	IRI      string
+	Literal  string
+	Lang     string
+	Datatype string
+}
+
+// rdfTriple は、RDF のトリプルを表す構造体です。
+type rdfTriple struct {
+	Subject   string
+	Predicate string
+	Object    rdfNode
+}
+
+// rdfGraph は、書き出すトリプルを主語ごとにまとめて保持する構造体です。
+type rdfGraph struct {
+	prefixes [][2]string
+	subjects []string
+	triples  map[string][]rdfTriple
+}
+
+// iri は、IRI の目的語を作成する関数です。
+func iri(s string) rdfNode {
+	return rdfNode{IRI: s}
+}
+
+// literal は、言語タグ付きの文字列リテラルを作成する関数です。
+func literal(s string, lang string) rdfNode {
+	return rdfNode{Literal: s, Lang: lang}
+}
+
+// typed は、データ型付きのリテラルを作成する関数です。
+func typed(s string, datatype string) rdfNode {
+	return rdfNode{Literal: s, Datatype: datatype}
+}
+
+// dateLiteral は、YYYY-MM-DD または YYYY-MM 形式の日付をデータ型付きのリテラルにする関数です。
+func dateLiteral(date string) rdfNode {
+	if len(date) == len("2006-01") {
+		return typed(date, xsdNS+"gYearMonth")
+	}
+	return typed(date, xsdNS+"date")
+}
+
+// add は、トリプルを追加するメソッドです。目的語が空の場合は追加しません。
+func (g *rdfGraph) add(subject string, predicate string, object rdfNode) {
+	if len(object.IRI) <= 0 && len(object.Literal) <= 0 {
+		return
+	}
+
+	if _, exists := g.triples[subject]; !exists {
+		g.subjects = append(g.subjects, subject)
+	}
+	g.triples[subject] = append(g.triples[subject], rdfTriple{Subject: subject, Predicate: predicate, Object: object})
+}
+
+// rdfIRIs は、基底IRIから各リソースの IRI を作成するための構造体です。
+// ワーキンググループと会議の IRI はIDから作成するため、再出力しても変わりません。
+// 人物の IRI は人物IDから作成しますが、名簿の委員の人物IDは parse のたびに無作為に割り当てるため、parse し直すと変わります。
+type rdfIRIs struct {
+	base string
+}
+
+func (r rdfIRIs) vocab(name string) string      { return r.base + "vocab/" + name }
+func (r rdfIRIs) council() string               { return r.base + "council/" + Council.ID }
+func (r rdfIRIs) workingGroup(id string) string { return r.base + "wg/" + url.PathEscape(id) }
+func (r rdfIRIs) meeting(id string) string      { return r.base + "meeting/" + url.PathEscape(id) }
+func (r rdfIRIs) person(id string) string       { return r.base + "person/" + url.PathEscape(id) }
+func (r rdfIRIs) membership(wgID, personID string) string {
+	return r.workingGroup(wgID) + "/member/" + url.PathEscape(personID)
+}
+func (r rdfIRIs) speech(meetingID string, turn int) string {
+	return r.meeting(meetingID) + "/speech/" + strconv.Itoa(turn)
+}
+func (r rdfIRIs) speaker(meetingID, label string) string {
+	return r.meeting(meetingID) + "/speaker/" + url.PathEscape(label)
+}
+
+// RDF は、Corpus のワーキンググループ・人物・会議・発言を RDF として filePath に書き出す関数です。
+// format には RDFTurtle または RDFNTriples を指定します。IRI は base に、ワーキンググループID・会議ID・人物IDを続けて作成します。
+// 名簿の委員の人物IDは parse のたびに変わるため、別の parse の結果から書き出した RDF とは人物の IRI が一致しません。
+func RDF(corpus *model.Corpus, filePath string, format string, base string) error {
+	if format != RDFTurtle && format != RDFNTriples {
+		return errors.New("RDFの形式 " + format + " には対応していません。")
+	}
+	if !strings.HasSuffix(base, "/") && !strings.HasSuffix(base, "#") {
+		base += "/"
+	}
+
+	graph := buildRDFGraph(corpus, rdfIRIs{base: base})
+	graph.prefixes = append(rdfPrefixes, [2]string{"meroku", base + "vocab/"})
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if format == RDFTurtle {
+		graph.writeTurtle(w)
+	} else {
+		graph.writeNTriples(w)
+	}
+
+	return w.Flush()
+}
+
+// buildRDFGraph は、Corpus の内容を rdfGraph に変換する関数です。
+func buildRDFGraph(corpus *model.Corpus, r rdfIRIs) *rdfGraph {
+	g := &rdfGraph{triples: map[string][]rdfTriple{}}
+	rdfType := rdfNS + "type"
+
+	council := r.council()
+	g.add(council, rdfType, iri(schemaNS+"GovernmentOrganization"))
+	g.add(council, schemaNS+"name", literal(Council.Name, "ja"))
+	g.add(council, schemaNS+"url", iri(Council.URL))
+
+	for _, id := range sortedWorkingGroupIDs(corpus.WorkingGroups) {
+		wg := corpus.WorkingGroups[id]
+		subject := r.workingGroup(id)
+		g.add(subject, rdfType, iri(schemaNS+"GovernmentOrganization"))
+		g.add(subject, rdfType, iri(foafNS+"Organization"))
+		g.add(subject, schemaNS+"identifier", literal(id, ""))
+		g.add(subject, schemaNS+"name", literal(wg.Name, "ja"))
+		if len(wg.URL) > 0 {
+			g.add(subject, schemaNS+"url", iri(wg.URL))
+		}
+		if len(wg.ParentID) > 0 {
+			g.add(subject, schemaNS+"parentOrganization", iri(r.workingGroup(wg.ParentID)))
+		} else {
+			g.add(subject, schemaNS+"parentOrganization", iri(council))
+		}
+		if len(wg.ActiveFrom) > 0 {
+			g.add(subject, schemaNS+"foundingDate", dateLiteral(wg.ActiveFrom))
+		}
+		if len(wg.ActiveUntil) > 0 {
+			g.add(subject, schemaNS+"dissolutionDate", dateLiteral(wg.ActiveUntil))
+		}
+	}
+
+	for _, person := range corpus.Persons() {
+		subject := r.person(person.ID)
+		g.add(subject, rdfType, iri(foafNS+"Person"))
+		g.add(subject, rdfType, iri(schemaNS+"Person"))
+		g.add(subject, schemaNS+"identifier", literal(person.ID, ""))
+		g.add(subject, foafNS+"name", literal(person.Name, "ja"))
+		// 名簿の「所属・職名」は所属先と職名をまとめた文字列のため、所属先（schema:Organization）にはせず文字列のまま書き出す
+		g.add(subject, schemaNS+"description", literal(person.Affiliation, "ja"))
+	}
+
+	// 所属は役職を持つため、schema:OrganizationRole で表す
+	for _, membership := range corpus.Memberships() {
+		subject := r.membership(membership.WorkingGroupID, membership.PersonID)
+		g.add(r.workingGroup(membership.WorkingGroupID), schemaNS+"member", iri(subject))
+		g.add(subject, rdfType, iri(schemaNS+"OrganizationRole"))
+		g.add(subject, schemaNS+"member", iri(r.person(membership.PersonID)))
+		g.add(subject, schemaNS+"roleName", literal(membership.Role, "ja"))
+	}
+
+	meetingIDs := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if meetingIDs[minutes.ID] {
+			continue
+		}
+		meetingIDs[minutes.ID] = true
+
+		meeting := r.meeting(minutes.ID)
+		g.add(meeting, rdfType, iri(schemaNS+"Event"))
+		g.add(meeting, schemaNS+"identifier", literal(minutes.ID, ""))
+		g.add(meeting, schemaNS+"name", literal(minutes.Title, "ja"))
+		g.add(meeting, schemaNS+"organizer", iri(r.workingGroup(minutes.WorkingGroupID)))
+		if minutes.MeetingNumber > 0 {
+			g.add(meeting, r.vocab("meetingNumber"), typed(strconv.Itoa(minutes.MeetingNumber), xsdNS+"integer"))
+		}
+		if len(minutes.Date) > 0 {
+			g.add(meeting, schemaNS+"startDate", dateLiteral(minutes.Date))
+		}
+		g.add(meeting, schemaNS+"location", literal(minutes.Venue, "ja"))
+		for _, topic := range minutes.Topics {
+			g.add(meeting, schemaNS+"about", literal(topic, "ja"))
+		}
+		if len(minutes.Provenance.SourceURL) > 0 {
+			g.add(meeting, dctermsNS+"source", iri(minutes.Provenance.SourceURL))
+		}
+		for _, material := range minutes.Materials {
+			if len(material.URL) > 0 {
+				g.add(meeting, dctermsNS+"relation", iri(material.URL))
+			}
+		}
+
+		for _, speach := range minutes.Speaches {
+			if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+				continue
+			}
+
+			speaker := r.speaker(minutes.ID, speach.Speaker.Label)
+			if len(speach.Speaker.Person.ID) > 0 {
+				speaker = r.person(speach.Speaker.Person.ID)
+			} else {
+				g.add(speaker, rdfType, iri(foafNS+"Agent"))
+				g.add(speaker, foafNS+"name", literal(speach.Speaker.Label, "ja"))
+			}
+
+			subject := r.speech(minutes.ID, speach.Turn)
+			g.add(subject, rdfType, iri(lpvNS+"Speech"))
+			g.add(subject, dctermsNS+"isPartOf", iri(meeting))
+			g.add(subject, lpvNS+"speaker", iri(speaker))
+			g.add(subject, lpvNS+"text", literal(speach.Text(), "ja"))
+			g.add(subject, schemaNS+"position", typed(strconv.Itoa(speach.Turn), xsdNS+"integer"))
+			g.add(subject, r.vocab("speakerLabel"), literal(speach.Speaker.Label, "ja"))
+			g.add(subject, r.vocab("roleClass"), literal(model.RoleClass(speach.Speaker), ""))
+			if len(minutes.Date) > 0 {
+				g.add(subject, dctermsNS+"date", dateLiteral(minutes.Date))
+			}
+		}
+	}
+
+	return g
+}
+
+// sortedWorkingGroupIDs は、ワーキンググループIDを昇順に並べて返す関数です。
+func sortedWorkingGroupIDs(wgList model.WorkingGroupList) []string {
+	ids := []string{}
+	for id := range wgList {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// escapeRDFLiteral は、Turtle と N-Triples の文字列リテラルで必要なエスケープを行う関数です。
+func escapeRDFLiteral(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s)
+}
+
+// escapeIRI は、IRI に含めることのできない文字をパーセントエンコードする関数です。
+func escapeIRI(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ntriplesTerm は、N-Triples の表記に変換する関数です。
+func ntriplesTerm(node rdfNode) string {
+	if len(node.IRI) > 0 {
+		return "<" + escapeIRI(node.IRI) + ">"
+	}
+
+	term := `"` + escapeRDFLiteral(node.Literal) + `"`
+	if len(node.Lang) > 0 {
+		term += "@" + node.Lang
+	} else if len(node.Datatype) > 0 {
+		term += "^^<" + escapeIRI(node.Datatype) + ">"
+	}
+	return term
+}
+
+// turtleIRI は、接頭辞で表記できる IRI を接頭辞付きの名前に変換するメソッドです。
+func (g *rdfGraph) turtleIRI(s string) string {
+	for _, prefix := range g.prefixes {
+		if strings.HasPrefix(s, prefix[1]) && rdfLocalNamePattern.MatchString(s[len(prefix[1]):]) {
+			return prefix[0] + ":" + s[len(prefix[1]):]
+		}
+	}
+	return "<" + escapeIRI(s) + ">"
+}
+
+// turtleTerm は、目的語を Turtle の表記に変換するメソッドです。
+func (g *rdfGraph) turtleTerm(node rdfNode) string {
+	if len(node.IRI) > 0 {
+		return g.turtleIRI(node.IRI)
+	}
+
+	term := `"` + escapeRDFLiteral(node.Literal) + `"`
+	if len(node.Lang) > 0 {
+		term += "@" + node.Lang
+	} else if len(node.Datatype) > 0 {
+		term += "^^" + g.turtleIRI(node.Datatype)
+	}
+	return term
+}
+
+// writeNTriples は、グラフを N-Triples で書き出すメソッドです。
+func (g *rdfGraph) writeNTriples(w *bufio.Writer) {
+	for _, subject := range g.subjects {
+		for _, triple := range g.triples[subject] {
+			w.WriteString(ntriplesTerm(iri(triple.Subject)) + " " + ntriplesTerm(iri(triple.Predicate)) + " " + ntriplesTerm(triple.Object) + " .\n")
+		}
+	}
+}
+
+// writeTurtle は、グラフを主語ごとにまとめた Turtle で書き出すメソッドです。
+func (g *rdfGraph) writeTurtle(w *bufio.Writer) {
+	for _, prefix := range g.prefixes {
+		w.WriteString("@prefix " + prefix[0] + ": <" + prefix[1] + "> .\n")
+	}
+
+	for _, subject := range g.subjects {
+		w.WriteString("\n" + g.turtleIRI(subject))
+		for i, triple := range g.triples[subject] {
+			predicate := g.turtleIRI(triple.Predicate)
+			if triple.Predicate == rdfNS+"type" {
+				predicate = "a"
+			}
+			separator := " ;\n    "
+			if i == 0 {
+				separator = "\n    "
+			}
+			w.WriteString(separator + predicate + " " + g.turtleTerm(triple.Object))
+		}
+		w.WriteString(" .\n")
+	}
+}
@@ -0,0 +1,246 @@
+// Package export は、パース済みの議事録を各種の外部形式で書き出すためのパッケージです。
+package export
+
+import (
+	"database/sql"
+	"log"
+	"os"
+
+	"github.com/tsunekawa/meroku/internal/model"
+
+	// cgo を使用しない SQLite ドライバー
+	_ "modernc.org/sqlite"
+)
+
+// Council は、ワーキンググループが属する審議会です。現在は中央教育審議会のみを扱います。
+var Council = struct {
+	ID   string
+	Name string
+	URL  string
+}{
+	ID:   "chukyo",
+	Name: model.CouncilName,
+	URL:  "https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/index.htm",
+}
+
+// sqliteSchema は、SQLite データベースのスキーマです。
+const sqliteSchema = `
+CREATE TABLE councils (
+	id   TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	url  TEXT
+);
+
+CREATE TABLE working_groups (
+	id            TEXT PRIMARY KEY,
+	council_id    TEXT NOT NULL REFERENCES councils(id),
+	parent_id     TEXT REFERENCES working_groups(id) DEFERRABLE INITIALLY DEFERRED,
+	name          TEXT,
+	url           TEXT,
+	status        TEXT,
+	active_from   TEXT,
+	active_until  TEXT,
+	display_order TEXT
+);
+
+CREATE TABLE meetings (
+	id             TEXT PRIMARY KEY,
+	wg_id          TEXT NOT NULL REFERENCES working_groups(id),
+	number         INTEGER,
+	title          TEXT,
+	date           TEXT,
+	venue          TEXT,
+	source_url     TEXT,
+	local_path     TEXT,
+	fetched_at     TEXT,
+	sha256         TEXT,
+	parser         TEXT,
+	parser_version TEXT,
+	meroku_version TEXT
+);
+
+CREATE TABLE persons (
+	id          TEXT PRIMARY KEY,
+	name        TEXT,
+	label       TEXT,
+	affiliation TEXT
+);
+
+CREATE TABLE memberships (
+	person_id TEXT NOT NULL REFERENCES persons(id),
+	wg_id     TEXT NOT NULL REFERENCES working_groups(id),
+	role      TEXT NOT NULL,
+	PRIMARY KEY (person_id, wg_id, role)
+);
+
+CREATE TABLE speakers (
+	meeting_id       TEXT NOT NULL REFERENCES meetings(id),
+	label            TEXT NOT NULL,
+	person_id        TEXT REFERENCES persons(id),
+	resolution_score REAL,
+	role_class       TEXT,
+	PRIMARY KEY (meeting_id, label)
+);
+
+CREATE TABLE speeches (
+	id             INTEGER PRIMARY KEY,
+	meeting_id     TEXT NOT NULL REFERENCES meetings(id),
+	turn           INTEGER NOT NULL,
+	speaker_label  TEXT NOT NULL,
+	person_id      TEXT REFERENCES persons(id),
+	role_class     TEXT,
+	text           TEXT,
+	char_count     INTEGER,
+	sentence_count INTEGER,
+	UNIQUE (meeting_id, turn),
+	FOREIGN KEY (meeting_id, speaker_label) REFERENCES speakers(meeting_id, label)
+);
+
+CREATE TABLE materials (
+	id         INTEGER PRIMARY KEY,
+	meeting_id TEXT NOT NULL REFERENCES meetings(id),
+	title      TEXT,
+	url        TEXT
+);
+
+CREATE INDEX speeches_person_id ON speeches(person_id);
+CREATE INDEX meetings_wg_id ON meetings(wg_id);
+
+-- trigram は3文字以上の語だけを MATCH で検索できる（2文字以下の語は LIKE で検索する）
+CREATE VIRTUAL TABLE speeches_fts USING fts5(
+	text,
+	content = 'speeches',
+	content_rowid = 'id',
+	tokenize = 'trigram'
+);
+`
+
+// SQLite は、Corpus を正規化したスキーマの SQLite データベースファイルとして書き出す関数です。
+// 既存のファイルは上書きされます。発言本文には FTS5（trigram）の全文検索インデックス speeches_fts が作成されます。
+// trigram は3文字未満の語を MATCH で検索できないため、「教員」のような2文字の語は speeches_fts.text に対する LIKE（'%教員%'）で検索してください。
+func SQLite(corpus *model.Corpus, filePath string) error {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+filePath+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := insertCorpus(tx, corpus); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO speeches_fts(speeches_fts) VALUES ('rebuild')`)
+	return err
+}
+
+// insertCorpus は、Corpus の内容をトランザクション内で各テーブルに挿入する関数です。
+func insertCorpus(tx *sql.Tx, corpus *model.Corpus) error {
+	if _, err := tx.Exec(`INSERT INTO councils (id, name, url) VALUES (?, ?, ?)`, Council.ID, Council.Name, Council.URL); err != nil {
+		return err
+	}
+
+	// 議事録や名簿から参照されているが一覧にないワーキンググループも、外部キーを満たすために登録する
+	workingGroups := model.WorkingGroupList{}
+	for id, wg := range corpus.WorkingGroups {
+		workingGroups[id] = wg
+	}
+	for _, minutes := range corpus.Minutes {
+		if _, exists := workingGroups[minutes.WorkingGroupID]; !exists {
+			workingGroups[minutes.WorkingGroupID] = model.WorkingGroup{ID: minutes.WorkingGroupID, Name: minutes.WorkingGroup}
+		}
+	}
+	for _, membership := range corpus.Memberships() {
+		if _, exists := workingGroups[membership.WorkingGroupID]; !exists {
+			workingGroups[membership.WorkingGroupID] = model.WorkingGroup{ID: membership.WorkingGroupID}
+		}
+	}
+	for _, wg := range corpus.WorkingGroups {
+		if _, exists := workingGroups[wg.ParentID]; !exists && len(wg.ParentID) > 0 {
+			workingGroups[wg.ParentID] = model.WorkingGroup{ID: wg.ParentID}
+		}
+	}
+
+	for _, wg := range workingGroups {
+		if _, err := tx.Exec(`INSERT INTO working_groups (id, council_id, parent_id, name, url, status, active_from, active_until, display_order) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			wg.ID, Council.ID, nullString(wg.ParentID), wg.Name, wg.URL, wg.Status, wg.ActiveFrom, wg.ActiveUntil, wg.Order); err != nil {
+			return err
+		}
+	}
+
+	for _, person := range corpus.Persons() {
+		if _, err := tx.Exec(`INSERT INTO persons (id, name, label, affiliation) VALUES (?, ?, ?, ?)`,
+			person.ID, person.Name, person.Label, person.Affiliation); err != nil {
+			return err
+		}
+	}
+
+	for _, membership := range corpus.Memberships() {
+		if _, err := tx.Exec(`INSERT INTO memberships (person_id, wg_id, role) VALUES (?, ?, ?)`,
+			membership.PersonID, membership.WorkingGroupID, membership.Role); err != nil {
+			return err
+		}
+	}
+
+	meetingIDs := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if meetingIDs[minutes.ID] {
+			log.Printf("WARN: 会議ID %v が重複しているため、%v を読み飛ばしました。\n", minutes.ID, minutes.Provenance.LocalPath)
+			continue
+		}
+		meetingIDs[minutes.ID] = true
+
+		p := minutes.Provenance
+		if _, err := tx.Exec(`INSERT INTO meetings (id, wg_id, number, title, date, venue, source_url, local_path, fetched_at, sha256, parser, parser_version, meroku_version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			minutes.ID, minutes.WorkingGroupID, minutes.MeetingNumber, minutes.Title, minutes.Date, minutes.Venue,
+			p.SourceURL, p.LocalPath, p.FetchedAt, p.SHA256, p.Parser, p.ParserVersion, p.MerokuVersion); err != nil {
+			return err
+		}
+
+		for _, speaker := range minutes.Speakers {
+			if _, err := tx.Exec(`INSERT INTO speakers (meeting_id, label, person_id, resolution_score, role_class) VALUES (?, ?, ?, ?, ?)`,
+				minutes.ID, speaker.Label, nullString(speaker.Person.ID), speaker.ResolutionScore, model.RoleClass(speaker)); err != nil {
+				return err
+			}
+		}
+
+		for _, record := range minutes.SpeechRecords() {
+			if _, err := tx.Exec(`INSERT INTO speeches (meeting_id, turn, speaker_label, person_id, role_class, text, char_count, sentence_count) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				record.MeetingID, record.Turn, record.SpeakerLabel, nullString(record.PersonID), record.RoleClass, record.Text, record.CharCount, record.SentenceCount); err != nil {
+				return err
+			}
+		}
+
+		for _, material := range minutes.Materials {
+			if _, err := tx.Exec(`INSERT INTO materials (meeting_id, title, url) VALUES (?, ?, ?)`,
+				minutes.ID, material.Title, material.URL); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// nullString は、空文字列を NULL として扱うための値に変換する関数です。
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
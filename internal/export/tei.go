@@ -0,0 +1,357 @@
+package export
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// teiNamespace は、TEI の名前空間です。
+const teiNamespace = "http://www.tei-c.org/ns/1.0"
+
+// stageDirectionPattern は、発言中の「（拍手）」「―― 了 ――」のようなト書き（発言以外の記述）を検出する正規表現です。
+var stageDirectionPattern = regexp.MustCompile(`^(?:[（(〔［][^（(）)]*[）)〕］]|[―─－ー\-\s　]+[^―─－]*[―─－ー\-]+)$`)
+
+// IsStageDirection は、段落が全体として括弧書きや罫線で囲まれたト書きであるかを判定する関数です。
+func IsStageDirection(talk string) bool {
+	return stageDirectionPattern.MatchString(strings.TrimSpace(talk))
+}
+
+// teiPersonID は、話者の TEI 上の xml:id を返す関数です。名寄せされていない話者は、ラベルからIDを作成します。
+func teiPersonID(speaker *model.Speaker) string {
+	if len(speaker.Person.ID) > 0 {
+		return "person." + speaker.Person.ID
+	}
+
+	return "speaker." + model.PersonID("label:"+speaker.Label)
+}
+
+// teiOrgID は、ワーキンググループの TEI 上の xml:id を返す関数です。
+func teiOrgID(wgID string) string {
+	return "wg." + wgID
+}
+
+// xmlWriter は、encoding/xml の Encoder を TEI の書き出し用に包んだ構造体です。最初に発生したエラーを保持します。
+type xmlWriter struct {
+	encoder *xml.Encoder
+	err     error
+}
+
+// newXMLWriter は、XML宣言を書き出したうえで xmlWriter を作成する関数です。
+func newXMLWriter(file *os.File) *xmlWriter {
+	w := &xmlWriter{encoder: xml.NewEncoder(file)}
+	w.encoder.Indent("", "  ")
+	_, w.err = file.WriteString(xml.Header)
+
+	return w
+}
+
+// start は、開始タグを書き出すメソッドです。attrs は名前と値を交互に指定します。値が空の属性は省略します。
+func (w *xmlWriter) start(name string, attrs ...string) {
+	if w.err != nil {
+		return
+	}
+
+	element := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if len(attrs[i+1]) > 0 {
+			element.Attr = append(element.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+		}
+	}
+	w.err = w.encoder.EncodeToken(element)
+}
+
+// end は、終了タグを書き出すメソッドです。
+func (w *xmlWriter) end(name string) {
+	if w.err != nil {
+		return
+	}
+	w.err = w.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// element は、テキストだけを内容とする要素を書き出すメソッドです。
+func (w *xmlWriter) element(name string, text string, attrs ...string) {
+	w.start(name, attrs...)
+	if w.err == nil && len(text) > 0 {
+		w.err = w.encoder.EncodeToken(xml.CharData(text))
+	}
+	w.end(name)
+}
+
+// close は、バッファを書き出して保持しているエラーを返すメソッドです。
+func (w *xmlWriter) close() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.encoder.Flush()
+}
+
+// TEI は、Corpus を ParlaMint の慣習に沿った TEI XML として outputdir に書き出す関数です。
+// 会議ごとの文書（<会議ID>.xml）、ワーキンググループの一覧（listOrg.xml）、名簿から作成した人物の一覧（listPerson.xml）と、
+// それらを XInclude で束ねるコーパス文書（corpus.xml）を作成します。
+func TEI(corpus *model.Corpus, outputdir string) error {
+	if err := os.MkdirAll(outputdir, 0777); err != nil {
+		return err
+	}
+
+	if err := writeTEIListOrg(corpus, filepath.Join(outputdir, "listOrg.xml")); err != nil {
+		return err
+	}
+	if err := writeTEIListPerson(corpus, filepath.Join(outputdir, "listPerson.xml")); err != nil {
+		return err
+	}
+
+	fileNames := []string{}
+	written := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if written[minutes.ID] {
+			continue
+		}
+		written[minutes.ID] = true
+
+		fileName := minutes.ID + ".xml"
+		if err := writeTEIMeeting(minutes, filepath.Join(outputdir, fileName)); err != nil {
+			return err
+		}
+		fileNames = append(fileNames, fileName)
+	}
+
+	return writeTEICorpus(fileNames, filepath.Join(outputdir, "corpus.xml"))
+}
+
+// writeTEICorpus は、各文書を XInclude で束ねる teiCorpus 文書を書き出す関数です。
+func writeTEICorpus(fileNames []string, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := newXMLWriter(file)
+	w.start("teiCorpus", "xmlns", teiNamespace, "xmlns:xi", "http://www.w3.org/2001/XInclude", "xml:id", "meroku", "xml:lang", "ja")
+	w.start("teiHeader")
+	w.start("fileDesc")
+	w.start("titleStmt")
+	w.element("title", Council.Name+" 議事録コーパス", "type", "main", "xml:lang", "ja")
+	w.end("titleStmt")
+	w.start("publicationStmt")
+	w.element("p", "meroku "+model.MerokuVersion+" により作成")
+	w.end("publicationStmt")
+	w.start("sourceDesc")
+	w.element("p", Council.URL)
+	w.end("sourceDesc")
+	w.end("fileDesc")
+
+	w.start("encodingDesc")
+	w.start("classDecl")
+	w.start("taxonomy", "xml:id", "speaker_types")
+	for _, category := range []struct{ id, desc string }{
+		{model.RoleClassMember, "委員"},
+		{model.RoleClassSecretariat, "事務局"},
+		{model.RoleClassOther, "その他"},
+	} {
+		w.start("category", "xml:id", category.id)
+		w.element("catDesc", category.desc, "xml:lang", "ja")
+		w.end("category")
+	}
+	w.end("taxonomy")
+	w.end("classDecl")
+	w.end("encodingDesc")
+
+	w.start("profileDesc")
+	w.start("particDesc")
+	w.element("xi:include", "", "href", "listOrg.xml")
+	w.element("xi:include", "", "href", "listPerson.xml")
+	w.end("particDesc")
+	w.end("profileDesc")
+	w.end("teiHeader")
+
+	for _, fileName := range fileNames {
+		w.element("xi:include", "", "href", fileName)
+	}
+	w.end("teiCorpus")
+
+	return w.close()
+}
+
+// writeTEIListOrg は、ワーキンググループの一覧を listOrg として書き出す関数です。
+func writeTEIListOrg(corpus *model.Corpus, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ids := []string{}
+	for id := range corpus.WorkingGroups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	w := newXMLWriter(file)
+	w.start("listOrg", "xmlns", teiNamespace, "xml:id", "meroku-listOrg", "xml:lang", "ja")
+	for _, id := range ids {
+		wg := corpus.WorkingGroups[id]
+		w.start("org", "xml:id", teiOrgID(id), "role", "committee")
+		w.element("orgName", wg.Name, "full", "yes")
+		if len(wg.ActiveFrom) > 0 || len(wg.ActiveUntil) > 0 {
+			w.element("event", "", "from", wg.ActiveFrom, "to", wg.ActiveUntil)
+		}
+		if len(wg.ParentID) > 0 {
+			w.element("relation", "", "name", "subOrganization", "active", "#"+teiOrgID(id), "passive", "#"+teiOrgID(wg.ParentID))
+		}
+		if len(wg.URL) > 0 {
+			w.element("idno", wg.URL, "type", "URI")
+		}
+		w.end("org")
+	}
+	w.end("listOrg")
+
+	return w.close()
+}
+
+// writeTEIListPerson は、名簿と議事録の話者から作成した人物の一覧を listPerson として書き出す関数です。
+// 名寄せされていない話者も、話者ラベルを名前とする人物として含めます。
+func writeTEIListPerson(corpus *model.Corpus, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	memberships := map[string][]model.Membership{}
+	for _, membership := range corpus.Memberships() {
+		memberships[membership.PersonID] = append(memberships[membership.PersonID], membership)
+	}
+
+	w := newXMLWriter(file)
+	w.start("listPerson", "xmlns", teiNamespace, "xml:id", "meroku-listPerson", "xml:lang", "ja")
+
+	for _, person := range corpus.Persons() {
+		w.start("person", "xml:id", "person."+person.ID)
+		w.element("persName", person.Name)
+		if len(person.Affiliation) > 0 {
+			w.element("occupation", person.Affiliation)
+		}
+		for _, membership := range memberships[person.ID] {
+			w.start("affiliation", "role", "member", "ref", "#"+teiOrgID(membership.WorkingGroupID))
+			w.element("roleName", membership.Role)
+			w.end("affiliation")
+		}
+		w.end("person")
+	}
+
+	// 名寄せされていない話者
+	unresolved := map[string]string{}
+	for _, minutes := range corpus.Minutes {
+		for _, speaker := range minutes.Speakers {
+			if len(speaker.Person.ID) <= 0 {
+				unresolved[teiPersonID(speaker)] = speaker.Label
+			}
+		}
+	}
+	ids := []string{}
+	for id := range unresolved {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		w.start("person", "xml:id", id)
+		w.element("persName", unresolved[id])
+		w.element("note", "名寄せされていない話者ラベル", "xml:lang", "ja")
+		w.end("person")
+	}
+
+	w.end("listPerson")
+
+	return w.close()
+}
+
+// writeTEIMeeting は、会議1件を TEI 文書として書き出す関数です。
+// 発言は <u who="#人物ID"> とし、段落ごとに <seg> を、ト書きには <note> を使用します。
+func writeTEIMeeting(minutes model.Minutes, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := newXMLWriter(file)
+	w.start("TEI", "xmlns", teiNamespace, "xml:id", minutes.ID, "xml:lang", "ja")
+
+	w.start("teiHeader")
+	w.start("fileDesc")
+	w.start("titleStmt")
+	w.element("title", minutes.Title, "type", "main")
+	number := ""
+	if minutes.MeetingNumber > 0 {
+		number = strconv.Itoa(minutes.MeetingNumber)
+	}
+	w.element("meeting", minutes.WorkingGroup, "n", number, "corresp", "#"+teiOrgID(minutes.WorkingGroupID))
+	w.end("titleStmt")
+	w.start("publicationStmt")
+	w.element("p", "meroku "+minutes.Provenance.MerokuVersion+" により作成")
+	w.end("publicationStmt")
+	w.start("sourceDesc")
+	w.start("bibl")
+	w.element("title", minutes.Title)
+	if len(minutes.Provenance.SourceURL) > 0 {
+		w.element("idno", minutes.Provenance.SourceURL, "type", "URI")
+	}
+	if len(minutes.Date) > 0 {
+		w.element("date", minutes.Date, "when", minutes.Date)
+	}
+	w.end("bibl")
+	w.end("sourceDesc")
+	w.end("fileDesc")
+	w.start("profileDesc")
+	w.start("settingDesc")
+	w.start("setting")
+	if len(minutes.Venue) > 0 {
+		w.element("name", minutes.Venue, "type", "place")
+	}
+	if len(minutes.Date) > 0 {
+		w.element("date", minutes.Date, "when", minutes.Date)
+	}
+	w.end("setting")
+	w.end("settingDesc")
+	w.end("profileDesc")
+	w.end("teiHeader")
+
+	w.start("text")
+	w.start("body")
+	w.start("div", "type", "debateSection")
+	for _, speach := range minutes.Speaches {
+		if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+			continue
+		}
+
+		utteranceID := minutes.ID + ".u" + strconv.Itoa(speach.Turn)
+		w.element("note", speach.Speaker.Label, "type", "speaker")
+		w.start("u", "who", "#"+teiPersonID(speach.Speaker), "ana", "#"+model.RoleClass(speach.Speaker), "xml:id", utteranceID)
+		for i, talk := range speach.Talks {
+			talk = strings.TrimSpace(strings.Trim(talk, "　"))
+			if len(talk) <= 0 {
+				continue
+			}
+			if IsStageDirection(talk) {
+				w.element("note", talk, "type", "comment")
+			} else {
+				w.element("seg", talk, "xml:id", utteranceID+"."+strconv.Itoa(i+1))
+			}
+		}
+		w.end("u")
+	}
+	w.end("div")
+	w.end("body")
+	w.end("text")
+	w.end("TEI")
+
+	return w.close()
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxMaxCellLength は、Excel のセルに格納できる最大の文字数です。
+const xlsxMaxCellLength = 32767
+
+// xlsxColumn は、ワークシートの列の見出しと書式です。
+type xlsxColumn struct {
+	Header string
+	Width  float64
+	Wrap   bool
+}
+
+// xlsxSheet は、ワークブックに書き出すワークシート1枚分のデータです。
+type xlsxSheet struct {
+	Name    string
+	Columns []xlsxColumn
+	Rows    [][]interface{}
+}
+
+// XLSX は、Corpus を Excel ワークブック（xlsx）として filePath に書き出す関数です。
+// ワーキンググループ・会議・人物・話者・発言のシートを作成し、見出し行を固定して長い本文は折り返して表示します。
+func XLSX(corpus *model.Corpus, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	cellStyle, err := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Vertical: "top"}})
+	if err != nil {
+		return err
+	}
+	wrapStyle, err := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Vertical: "top", WrapText: true}})
+	if err != nil {
+		return err
+	}
+
+	for i, sheet := range xlsxSheets(corpus) {
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), sheet.Name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sheet.Name); err != nil {
+			return err
+		}
+
+		if err := writeXLSXSheet(f, sheet, headerStyle, cellStyle, wrapStyle); err != nil {
+			return err
+		}
+	}
+	f.SetActiveSheet(0)
+
+	return f.SaveAs(filePath)
+}
+
+// writeXLSXSheet は、ワークシート1枚分のデータを書き出す関数です。行数が多くなるため StreamWriter を使用します。
+func writeXLSXSheet(f *excelize.File, sheet xlsxSheet, headerStyle int, cellStyle int, wrapStyle int) error {
+	sw, err := f.NewStreamWriter(sheet.Name)
+	if err != nil {
+		return err
+	}
+
+	for i, column := range sheet.Columns {
+		if err := sw.SetColWidth(i+1, i+1, column.Width); err != nil {
+			return err
+		}
+	}
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	header := []interface{}{}
+	for _, column := range sheet.Columns {
+		header = append(header, excelize.Cell{StyleID: headerStyle, Value: column.Header})
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for i, row := range sheet.Rows {
+		cells := []interface{}{}
+		for j, value := range row {
+			style := cellStyle
+			if sheet.Columns[j].Wrap {
+				style = wrapStyle
+			}
+			if s, ok := value.(string); ok && utf8.RuneCountInString(s) > xlsxMaxCellLength {
+				log.Printf("WARN: %vシート%v行%v列の文字数がExcelの上限を超えるため、%v文字で切り詰めました。\n", sheet.Name, i+2, j+1, xlsxMaxCellLength)
+				value = string([]rune(s)[:xlsxMaxCellLength])
+			}
+			cells = append(cells, excelize.Cell{StyleID: style, Value: value})
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+
+	return sw.Flush()
+}
+
+// xlsxSheets は、Corpus から各ワークシートのデータを作成する関数です。
+func xlsxSheets(corpus *model.Corpus) []xlsxSheet {
+	wgSheet := xlsxSheet{Name: "working_groups", Columns: []xlsxColumn{
+		{"id", 16, false}, {"name", 50, true}, {"parent_id", 16, false}, {"status", 10, false},
+		{"active_from", 12, false}, {"active_until", 12, false}, {"url", 40, false}, {"meeting_count", 14, false},
+	}}
+	meetingSheet := xlsxSheet{Name: "meetings", Columns: []xlsxColumn{
+		{"id", 16, false}, {"wg_id", 16, false}, {"number", 8, false}, {"title", 50, true},
+		{"date", 12, false}, {"venue", 30, true}, {"speech_count", 14, false}, {"source_url", 40, false},
+	}}
+	personSheet := xlsxSheet{Name: "persons", Columns: []xlsxColumn{
+		{"id", 38, false}, {"name", 16, false}, {"affiliation", 40, true}, {"memberships", 40, true},
+	}}
+	speakerSheet := xlsxSheet{Name: "speakers", Columns: []xlsxColumn{
+		{"meeting_id", 16, false}, {"label", 24, false}, {"person_id", 38, false}, {"person_name", 16, false},
+		{"resolution_score", 16, false}, {"role_class", 12, false},
+	}}
+	speechSheet := xlsxSheet{Name: "speeches", Columns: []xlsxColumn{
+		{"meeting_id", 16, false}, {"wg_id", 16, false}, {"date", 12, false}, {"turn", 8, false},
+		{"speaker_label", 24, false}, {"person_id", 38, false}, {"role_class", 12, false}, {"text", 80, true},
+		{"char_count", 12, false}, {"sentence_count", 14, false},
+	}}
+
+	meetingCounts := map[string]int{}
+	meetingIDs := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if meetingIDs[minutes.ID] {
+			continue
+		}
+		meetingIDs[minutes.ID] = true
+		meetingCounts[minutes.WorkingGroupID]++
+
+		records := minutes.SpeechRecords()
+		meetingSheet.Rows = append(meetingSheet.Rows, []interface{}{
+			minutes.ID, minutes.WorkingGroupID, minutes.MeetingNumber, minutes.Title,
+			minutes.Date, minutes.Venue, len(records), minutes.Provenance.SourceURL,
+		})
+
+		labels := []string{}
+		for label := range minutes.Speakers {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			speaker := minutes.Speakers[label]
+			speakerSheet.Rows = append(speakerSheet.Rows, []interface{}{
+				minutes.ID, speaker.Label, speaker.Person.ID, speaker.Person.Name,
+				speaker.ResolutionScore, model.RoleClass(speaker),
+			})
+		}
+
+		for _, record := range records {
+			speechSheet.Rows = append(speechSheet.Rows, []interface{}{
+				record.MeetingID, record.WorkingGroupID, record.Date, record.Turn,
+				record.SpeakerLabel, record.PersonID, record.RoleClass, record.Text,
+				record.CharCount, record.SentenceCount,
+			})
+		}
+	}
+
+	for _, id := range sortedWorkingGroupIDs(corpus.WorkingGroups) {
+		wg := corpus.WorkingGroups[id]
+		wgSheet.Rows = append(wgSheet.Rows, []interface{}{
+			id, wg.Name, wg.ParentID, wg.Status, wg.ActiveFrom, wg.ActiveUntil, wg.URL, meetingCounts[id],
+		})
+	}
+
+	memberships := map[string][]string{}
+	for _, membership := range corpus.Memberships() {
+		memberships[membership.PersonID] = append(memberships[membership.PersonID], membership.WorkingGroupID+":"+membership.Role)
+	}
+	for _, person := range corpus.Persons() {
+		personSheet.Rows = append(personSheet.Rows, []interface{}{
+			person.ID, person.Name, person.Affiliation, strings.Join(memberships[person.ID], "\n"),
+		})
+	}
+
+	return []xlsxSheet{wgSheet, meetingSheet, personSheet, speakerSheet, speechSheet}
+}
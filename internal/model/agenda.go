@@ -0,0 +1,119 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+// chairKeywords は、議事を進行する話者（主査・座長・部会長など）の話者ラベルに含まれる語です。
+var chairKeywords = []string{"主査", "座長", "会長", "委員長", "議長"}
+
+// agendaNumberPattern は、議題の一覧の項目の先頭にある番号（（１）、1．、①など）を検出する正規表現です。
+var agendaNumberPattern = regexp.MustCompile(`^[\s　]*(?:[（(]([0-9０-９]+|[一二三四五六七八九十]+)[）)]|([0-9０-９]+)[．.、]|([①-⑳]))`)
+
+// agendaCuePattern は、「議題２に移ります」「議題（３）に入ります」のように、番号を示して次の議題に進む発言を検出する正規表現です。
+var agendaCuePattern = regexp.MustCompile(`議題\s*[（(]?([0-9０-９]+|[一二三四五六七八九十]+)[）)]?(?:つ目|番目)?[^。]{0,30}?(?:移|入|進|始め|参り)`)
+
+// nextAgendaCuePattern は、「次の議題に移ります」のように、番号を示さずに次の議題に進む発言を検出する正規表現です。
+var nextAgendaCuePattern = regexp.MustCompile(`(?:次の|続いての|続きまして)議題[^。]{0,30}?(?:移|入|進|始め|参り)`)
+
+// AgendaNumber は、議題の一覧の項目の先頭にある番号を返す関数です。番号がない場合は false を返します。
+func AgendaNumber(topic string) (int, bool) {
+	match := agendaNumberPattern.FindStringSubmatch(topic)
+	if match == nil {
+		return 0, false
+	}
+	if len(match[1]) > 0 {
+		return ParseJapaneseNumber(match[1])
+	}
+	if len(match[2]) > 0 {
+		return ParseJapaneseNumber(match[2])
+	}
+
+	return int([]rune(match[3])[0]-'①') + 1, true
+}
+
+// AgendaCue は、議事の進行の発言から、次に扱う議題の番号を返す関数です。
+// 番号を示さずに「次の議題」に進む場合は、current の次の番号を返します。議題を移る発言でない場合は false を返します。
+func AgendaCue(talk string, current int) (int, bool) {
+	if match := agendaCuePattern.FindStringSubmatch(talk); match != nil {
+		return ParseJapaneseNumber(match[1])
+	}
+	if nextAgendaCuePattern.MatchString(talk) {
+		return current + 1, true
+	}
+
+	return 0, false
+}
+
+// IsChair は、話者が議事を進行する役（主査・座長・部会長など）かを判定する関数です。
+func IsChair(speaker *Speaker) bool {
+	if speaker == nil {
+		return false
+	}
+	for _, keyword := range chairKeywords {
+		if strings.Contains(speaker.Label, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SegmentAgenda は、議事を進行する話者の「議題２に移ります」のような発言を手がかりに、各発言が扱っている議題の番号を AgendaItem に設定するメソッドです。
+// 議題を移る発言はその議題に含め、最初の議題に移るまでの発言（開会のあいさつなど）は 0 とします。
+// 議題の一覧（Topics）がある場合、一覧にない番号への移行は無視します。議題がひとつだけで移行の発言がない場合は、すべての発言をその議題とします。
+func (m *Minutes) SegmentAgenda() {
+	current := 0
+	found := false
+
+	for _, speach := range m.Speaches {
+		if speach == nil {
+			continue
+		}
+
+		if IsChair(speach.Speaker) {
+			for _, talk := range speach.Talks {
+				next, ok := AgendaCue(talk, current)
+				if !ok || next <= 0 || (len(m.Topics) > 0 && next > len(m.Topics)) {
+					continue
+				}
+				current, found = next, true
+			}
+		}
+		speach.AgendaItem = current
+	}
+
+	if !found && len(m.Topics) == 1 {
+		for _, speach := range m.Speaches {
+			if speach != nil {
+				speach.AgendaItem = 1
+			}
+		}
+	}
+}
+
+// hasAgendaItems は、いずれかの発言に議題の番号が設定されているかを判定するメソッドです。
+func (m Minutes) hasAgendaItems() bool {
+	for _, speach := range m.Speaches {
+		if speach != nil && speach.AgendaItem > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AgendaTitle は、議題の番号に対応する議題の一覧の項目を返すメソッドです。
+// 項目の先頭の番号と一致するものを優先し、番号のない一覧では順番で対応させます。一覧にない番号の場合は空文字列を返します。
+func (m Minutes) AgendaTitle(item int) string {
+	for _, topic := range m.Topics {
+		if number, ok := AgendaNumber(topic); ok && number == item {
+			return topic
+		}
+	}
+	if item <= 0 || item > len(m.Topics) {
+		return ""
+	}
+
+	return m.Topics[item-1]
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Corpus は、parse コマンドの出力ディレクトリに含まれるデータ一式（ワーキンググループ・議事録・名簿）を表す構造体です。
+type Corpus struct {
+	WorkingGroups WorkingGroupList
+	Minutes       MinutesArray
+	MemberLists   []MemberList
+}
+
+// Membership は、人物がワーキンググループに所属していることを表す構造体です。
+type Membership struct {
+	PersonID       string
+	WorkingGroupID string
+	Role           string
+}
+
+// LoadCorpus は、parse コマンドの出力ディレクトリから Corpus を読み込む関数です。
+// working-groups.json と memberlist ディレクトリは、存在しない場合は空として扱います。
+// 発言に議題の番号（AgendaItem）が設定されていない議事録は、読み込み時に SegmentAgenda で区分します。
+func LoadCorpus(dir string) (*Corpus, error) {
+	corpus := &Corpus{WorkingGroups: WorkingGroupList{}}
+
+	minutesArray, err := LoadMinutesArray(dir)
+	if err != nil {
+		return nil, err
+	}
+	corpus.Minutes = minutesArray
+
+	// 議題の区分が導入される前に出力された議事録は、読み込み時に区分する
+	for i := range corpus.Minutes {
+		if !corpus.Minutes[i].hasAgendaItems() {
+			corpus.Minutes[i].SegmentAgenda()
+		}
+	}
+
+	wgListPath := filepath.Join(dir, "working-groups.json")
+	if _, err := os.Stat(wgListPath); err == nil {
+		corpus.WorkingGroups = ImportWorkingGroupList(wgListPath)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "memberlist", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var memberList MemberList
+		if err := json.Unmarshal(raw, &memberList); err != nil {
+			return nil, err
+		}
+
+		// 古い出力ではワーキンググループが記録されていないため、ファイル名から補う
+		if memberList.WorkingGroup == nil {
+			if wgID, ok := WorkingGroupIDFromFileName(file); ok {
+				wg, exists := corpus.WorkingGroups[wgID]
+				if !exists {
+					wg = WorkingGroup{ID: wgID}
+				}
+				memberList.WorkingGroup = &wg
+			}
+		}
+
+		corpus.MemberLists = append(corpus.MemberLists, memberList)
+	}
+
+	return corpus, nil
+}
+
+// Persons は、名簿と名寄せ済みの話者に現れる人物を、IDの重複を除いてID順に返すメソッドです。
+func (corpus *Corpus) Persons() []Person {
+	persons := map[string]Person{}
+
+	for _, memberList := range corpus.MemberLists {
+		for _, member := range memberList.Members {
+			if _, exists := persons[member.ID]; !exists && len(member.ID) > 0 {
+				persons[member.ID] = *member
+			}
+		}
+	}
+
+	for _, minutes := range corpus.Minutes {
+		for _, speaker := range minutes.Speakers {
+			if _, exists := persons[speaker.Person.ID]; !exists && len(speaker.Person.ID) > 0 {
+				persons[speaker.Person.ID] = speaker.Person
+			}
+		}
+	}
+
+	ids := []string{}
+	for id := range persons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := []Person{}
+	for _, id := range ids {
+		result = append(result, persons[id])
+	}
+
+	return result
+}
+
+// Memberships は、名簿から人物とワーキンググループの所属関係を重複を除いて返すメソッドです。
+func (corpus *Corpus) Memberships() []Membership {
+	memberships := []Membership{}
+	seen := map[Membership]bool{}
+
+	for _, memberList := range corpus.MemberLists {
+		if memberList.WorkingGroup == nil {
+			continue
+		}
+
+		for _, member := range memberList.Members {
+			membership := Membership{PersonID: member.ID, WorkingGroupID: memberList.WorkingGroup.ID, Role: member.Role}
+			if seen[membership] {
+				continue
+			}
+			seen[membership] = true
+			memberships = append(memberships, membership)
+		}
+	}
+
+	return memberships
+}
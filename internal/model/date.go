@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// eraOffsets は、元号と「元号の年 + offset = 西暦」となる offset の対応表です。
+var eraOffsets = map[string]int{
+	"令和": 2018,
+	"平成": 1988,
+	"昭和": 1925,
+}
+
+// japaneseDatePattern は、和暦・西暦で書かれた日付（日は省略可）を検出する正規表現です。
+var japaneseDatePattern = regexp.MustCompile(`(令和|平成|昭和)?[\s　]*([0-9０-９〇一二三四五六七八九十]+|元)[\s　]*年[\s　]*([0-9０-９一二三四五六七八九十]+)[\s　]*月(?:[\s　]*([0-9０-９一二三四五六七八九十]+)[\s　]*日)?`)
+
+// periodSeparatorPattern は、期間の開始と終了を区切る記号を検出する正規表現です。
+var periodSeparatorPattern = regexp.MustCompile(`[～〜~－―\-]`)
+
+// ParseJapaneseDate は、文字列に含まれる最初の日付を ISO 8601 形式（日がない場合は YYYY-MM）に変換する関数です。
+// 「令和元年」のような元年表記や、全角数字・漢数字にも対応しています。
+func ParseJapaneseDate(s string) (string, bool) {
+	matches := japaneseDatePattern.FindStringSubmatch(s)
+	if len(matches) < 5 {
+		return "", false
+	}
+
+	return formatJapaneseDate(matches)
+}
+
+// formatJapaneseDate は、japaneseDatePattern のマッチ結果を ISO 8601 形式の文字列に変換する関数です。
+func formatJapaneseDate(matches []string) (string, bool) {
+	year := 1
+	if matches[2] != "元" {
+		var ok bool
+		year, ok = ParseJapaneseNumber(matches[2])
+		if !ok {
+			return "", false
+		}
+	}
+
+	if offset, exists := eraOffsets[matches[1]]; exists {
+		year += offset
+	} else if year < 1000 {
+		// 元号のない2桁以下の年は解釈できない
+		return "", false
+	}
+
+	month, ok := ParseJapaneseNumber(matches[3])
+	if !ok || month < 1 || month > 12 {
+		return "", false
+	}
+
+	if len(matches[4]) <= 0 {
+		return fmt.Sprintf("%04d-%02d", year, month), true
+	}
+
+	day, ok := ParseJapaneseNumber(matches[4])
+	if !ok || day < 1 || day > 31 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day), true
+}
+
+// ParsePeriod は、「平成27年4月～平成29年3月」のような期間の表記から開始日と終了日を取り出す関数です。
+// 終了側の元号が省略されている場合は、開始側の元号を引き継ぎます。終了日が書かれていない場合は空文字列を返します。
+func ParsePeriod(s string) (from string, until string) {
+	locations := japaneseDatePattern.FindAllStringSubmatchIndex(s, 2)
+	if len(locations) <= 0 {
+		return "", ""
+	}
+
+	first := submatches(s, locations[0])
+	from, _ = formatJapaneseDate(first)
+
+	if len(locations) < 2 {
+		return from, ""
+	}
+
+	// 2つの日付の間に区切り記号がなければ期間とはみなさない
+	if !periodSeparatorPattern.MatchString(s[locations[0][1]:locations[1][0]]) {
+		return from, ""
+	}
+
+	second := submatches(s, locations[1])
+	if len(second[1]) <= 0 {
+		second[1] = first[1]
+	}
+	until, _ = formatJapaneseDate(second)
+
+	return from, until
+}
+
+// submatches は、FindAllStringSubmatchIndex の結果から部分文字列の配列を作る関数です。
+func submatches(s string, location []int) []string {
+	result := make([]string, len(location)/2)
+	for i := range result {
+		if location[2*i] >= 0 {
+			result[i] = s[location[2*i]:location[2*i+1]]
+		}
+	}
+
+	return result
+}
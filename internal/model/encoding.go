@@ -0,0 +1,235 @@
+package model
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// CSVEncoding は、CSVファイルを書き出す際の文字コードを表す型です。
+type CSVEncoding string
+
+// 書き出しに使用できる文字コードです。
+const (
+	EncodingUTF8     CSVEncoding = "utf-8"
+	EncodingUTF8BOM  CSVEncoding = "utf-8-bom"
+	EncodingShiftJIS CSVEncoding = "shift_jis"
+	EncodingCP932    CSVEncoding = "cp932"
+)
+
+// unrepresentableReplacement は、指定した文字コードで表現できない文字の代わりに書き出す文字（げた記号）です。
+const unrepresentableReplacement = "〓"
+
+// ParseCSVEncoding は、コマンドラインなどで指定された文字コード名を CSVEncoding に変換する関数です。
+func ParseCSVEncoding(name string) (CSVEncoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "utf-8", "utf8":
+		return EncodingUTF8, nil
+	case "utf-8-bom", "utf8-bom", "utf-8bom", "utf8bom":
+		return EncodingUTF8BOM, nil
+	case "shift_jis", "shift-jis", "sjis":
+		return EncodingShiftJIS, nil
+	case "cp932", "windows-31j", "ms932":
+		return EncodingCP932, nil
+	}
+
+	return "", errors.New(name + "は対応していない文字コードです。(utf-8, utf-8-bom, shift_jis, cp932)")
+}
+
+// EncodingIssue は、指定した文字コードで表現できずに置き換えた文字を表す構造体です。
+type EncodingIssue struct {
+	Row    int
+	Column int
+	Char   string
+	Field  string
+}
+
+// CSVWriter は、指定した文字コードでCSVを書き出し、表現できない文字を記録する構造体です。
+type CSVWriter struct {
+	Encoding CSVEncoding
+	Issues   []EncodingIssue
+	writer   *csv.Writer
+	row      int
+}
+
+// NewCSVWriter は、w に指定した文字コードでCSVを書き出す CSVWriter を作成する関数です。
+// UTF-8（BOM付き）の場合は、先頭にBOMを書き出します。
+func NewCSVWriter(w io.Writer, encoding CSVEncoding) (*CSVWriter, error) {
+	w, err := encodedWriter(w, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVWriter{Encoding: encoding, writer: csv.NewWriter(w)}, nil
+}
+
+// encodedWriter は、w に書き込む内容を指定した文字コードに変換する io.Writer を返す関数です。
+// UTF-8（BOM付き）の場合は、先頭にBOMを書き出します。
+func encodedWriter(w io.Writer, encoding CSVEncoding) (io.Writer, error) {
+	switch encoding {
+	case EncodingUTF8:
+	case EncodingUTF8BOM:
+		if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return nil, err
+		}
+	case EncodingShiftJIS, EncodingCP932:
+		w = transform.NewWriter(w, japanese.ShiftJIS.NewEncoder())
+	default:
+		return nil, errors.New(string(encoding) + "は対応していない文字コードです。")
+	}
+
+	return w, nil
+}
+
+// SetDelimiter は、区切り文字を変更するメソッドです。TSV を書き出す場合は '\t' を指定します。
+func (w *CSVWriter) SetDelimiter(delimiter rune) {
+	w.writer.Comma = delimiter
+}
+
+// Write は、1行分のレコードを書き出すメソッドです。表現できない文字は「〓」に置き換え、Issues に記録します。
+func (w *CSVWriter) Write(record []string) error {
+	sanitized := make([]string, len(record))
+	for column, field := range record {
+		sanitized[column] = w.sanitize(field, column)
+	}
+	w.row++
+
+	return w.writer.Write(sanitized)
+}
+
+// Flush は、バッファに残っているデータを書き出すメソッドです。
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// sanitize は、フィールド中の表現できない文字を置き換えるメソッドです。
+func (w *CSVWriter) sanitize(field string, column int) string {
+	sanitized, issues := sanitize(w.Encoding, field, w.row, column)
+	w.Issues = append(w.Issues, issues...)
+
+	return sanitized
+}
+
+// sanitize は、文字列中の指定した文字コードで表現できない文字を「〓」に置き換え、置き換えた文字を返す関数です。
+func sanitize(encoding CSVEncoding, field string, row, column int) (string, []EncodingIssue) {
+	if encoding == EncodingUTF8 || encoding == EncodingUTF8BOM {
+		return field, nil
+	}
+
+	issues := []EncodingIssue{}
+	var builder strings.Builder
+	for _, r := range field {
+		if representable(encoding, r) {
+			builder.WriteRune(r)
+			continue
+		}
+
+		issues = append(issues, EncodingIssue{Row: row, Column: column, Char: string(r), Field: field})
+		builder.WriteString(unrepresentableReplacement)
+	}
+
+	return builder.String(), issues
+}
+
+// representable は、文字を指定した文字コードで表現できるかどうかを判定する関数です。
+// shift_jis の場合は、CP932 で拡張された NEC特殊文字（13区）・NEC選定IBM拡張文字・IBM拡張文字を表現できないものとして扱います。
+func representable(encoding CSVEncoding, r rune) bool {
+	if r < 0x80 {
+		return true
+	}
+
+	encoded, err := japanese.ShiftJIS.NewEncoder().String(string(r))
+	if err != nil {
+		return false
+	}
+
+	if encoding == EncodingShiftJIS && len(encoded) == 2 {
+		lead := encoded[0]
+		if lead == 0x87 || lead == 0xED || lead == 0xEE || lead >= 0xFA {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReportIssues は、Issues の内容を文字ごとにまとめてログに出力するメソッドです。
+func (w *CSVWriter) ReportIssues(fileName string) {
+	reportIssues(fileName, w.Encoding, w.Issues)
+}
+
+// TextWriter は、指定した文字コードでテキストを1行ずつ書き出し、表現できない文字を記録する構造体です。
+type TextWriter struct {
+	Encoding CSVEncoding
+	Issues   []EncodingIssue
+	writer   *bufio.Writer
+	row      int
+}
+
+// NewTextWriter は、w に指定した文字コードでテキストを書き出す TextWriter を作成する関数です。
+// UTF-8（BOM付き）の場合は、先頭にBOMを書き出します。
+func NewTextWriter(w io.Writer, encoding CSVEncoding) (*TextWriter, error) {
+	w, err := encodedWriter(w, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TextWriter{Encoding: encoding, writer: bufio.NewWriter(w)}, nil
+}
+
+// WriteLine は、1行分のテキストを改行を付けて書き出すメソッドです。表現できない文字は「〓」に置き換え、Issues に記録します。
+func (w *TextWriter) WriteLine(line string) error {
+	sanitized, issues := sanitize(w.Encoding, line, w.row, 0)
+	w.Issues = append(w.Issues, issues...)
+	w.row++
+
+	_, err := w.writer.WriteString(sanitized + "\n")
+	return err
+}
+
+// Flush は、バッファに残っているデータを書き出すメソッドです。
+func (w *TextWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// ReportIssues は、Issues の内容を文字ごとにまとめてログに出力するメソッドです。
+func (w *TextWriter) ReportIssues(fileName string) {
+	reportIssues(fileName, w.Encoding, w.Issues)
+}
+
+// reportIssues は、表現できずに置き換えた文字を、文字ごとにまとめてログに出力する関数です。
+func reportIssues(fileName string, encoding CSVEncoding, issues []EncodingIssue) {
+	counts := map[string]int{}
+	examples := map[string]EncodingIssue{}
+	for _, issue := range issues {
+		if counts[issue.Char] == 0 {
+			examples[issue.Char] = issue
+		}
+		counts[issue.Char]++
+	}
+
+	chars := []string{}
+	for char := range counts {
+		chars = append(chars, char)
+	}
+	sort.Strings(chars)
+
+	for _, char := range chars {
+		log.Printf("WARN: %v: %v は %v で表現できないため「%v」に置き換えました（全%v件）\n",
+			fileName, examples[char], encoding, unrepresentableReplacement, counts[char])
+	}
+}
+
+// String は、EncodingIssue を読みやすい文字列として返すメソッドです。
+func (issue EncodingIssue) String() string {
+	return fmt.Sprintf("%v行%v列「%v」中の「%v」(U+%04X)", issue.Row+1, issue.Column+1, issue.Field, issue.Char, []rune(issue.Char)[0])
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// headerTagPattern は、見出しの値からHTMLタグを取り除くための正規表現です。
+var headerTagPattern = regexp.MustCompile(`<("[^"]*"|'[^']*'|[^'">])*>`)
+
+// headerLabelPattern は、「１．日時」「日時：」のような項目見出しを検出する正規表現です。
+var headerLabelPattern = regexp.MustCompile(`^[0-9０-９]*[．.、]?[\s　]*(日時|場所|議題)[\s　]*[：:]?[\s　]*`)
+
+// headerEndPattern は、「議題」などの値の後に続く、値に含めない見出し（出席者・議事録など）を検出する正規表現です。
+var headerEndPattern = regexp.MustCompile(`^[0-9０-９]+[．.、][\s　]*(出席者|欠席者|議事録|議事|配付資料|配布資料|資料)[\s　]*[：:]?[\s　]*$`)
+
+// headerValues は、議事録冒頭の「日時」「場所」「議題」などの項目の値を行ごとに抜き出す関数です。
+// 見出し（h2, h3）の次の要素に値が書かれている形式と、p 要素の中で <br/> 区切りで書かれている形式の両方に対応しています。
+func headerValues(doc *goquery.Document, keyword string) []string {
+	values := []string{}
+
+	doc.Find("h2, h3, h4").EachWithBreak(func(idx int, s *goquery.Selection) bool {
+		if !strings.Contains(s.Text(), keyword) || len([]rune(strings.TrimSpace(s.Text()))) > 10 {
+			return true
+		}
+		html, _ := s.Next().Html()
+		values = splitHeaderLines(html)
+		return false
+	})
+	if len(values) > 0 {
+		return values
+	}
+
+	doc.Find("p").EachWithBreak(func(idx int, s *goquery.Selection) bool {
+		html, _ := s.Html()
+		lines := splitHeaderLines(html)
+		for i, line := range lines {
+			label := headerLabelPattern.FindStringSubmatch(line)
+			if len(label) < 2 || label[1] != keyword {
+				continue
+			}
+
+			// 「日時：令和２年…」のように見出しと値が同じ行にある場合
+			if rest := strings.TrimSpace(line[len(label[0]):]); len(rest) > 0 {
+				values = append(values, rest)
+				return false
+			}
+
+			// 次の見出しが現れるまでの行を値とする
+			for _, next := range lines[i+1:] {
+				if headerLabelPattern.MatchString(next) || headerEndPattern.MatchString(next) {
+					break
+				}
+				values = append(values, next)
+			}
+			return false
+		}
+		return true
+	})
+
+	return values
+}
+
+// splitHeaderLines は、HTML断片を <br/> で分割し、タグを取り除いた空でない行の配列を返す関数です。
+func splitHeaderLines(html string) []string {
+	lines := []string{}
+	for _, element := range regexp.MustCompile(`(?i)<br\s*/?>`).Split(html, -1) {
+		line := strings.TrimSpace(headerTagPattern.ReplaceAllString(element, ""))
+		line = strings.Trim(line, "　")
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// parseHeader は、議事録冒頭の項目から開催日・場所・議題を読み取って Minutes に格納するメソッドです。
+func (m *Minutes) parseHeader(doc *goquery.Document) {
+	for _, value := range headerValues(doc, "日時") {
+		if date, ok := ParseJapaneseDate(value); ok {
+			m.Date = date
+			break
+		}
+	}
+
+	if venues := headerValues(doc, "場所"); len(venues) > 0 {
+		m.Venue = venues[0]
+	}
+
+	m.Topics = headerValues(doc, "議題")
+}
+
+// materialLinkPattern は、配付資料とみなすファイルへのリンクを検出する正規表現です。
+var materialLinkPattern = regexp.MustCompile(`(?i)\.(pdf|docx?|xlsx?|pptx?|zip)$`)
+
+// Material は、議事録のページからリンクされている配付資料を表す構造体です。
+type Material struct {
+	Title string
+	URL   string
+}
+
+// parseMaterials は、議事録のページからリンクされている配付資料を抽出して Minutes に格納するメソッドです。
+// 相対URLは、取得元URLが判明した後に resolveMaterialURLs で絶対URLに変換します。
+func (m *Minutes) parseMaterials(doc *goquery.Document) {
+	doc.Find("div#contentsMain a").Each(func(idx int, s *goquery.Selection) {
+		href, exists := s.Attr("href")
+		if !exists || !materialLinkPattern.MatchString(href) {
+			return
+		}
+
+		m.Materials = append(m.Materials, Material{
+			Title: strings.TrimSpace(s.Text()),
+			URL:   href,
+		})
+	})
+}
+
+// resolveMaterialURLs は、配付資料の相対URLを取得元URLを基準に絶対URLに変換するメソッドです。
+func (m *Minutes) resolveMaterialURLs() {
+	baseURL, err := url.Parse(m.Provenance.SourceURL)
+	if err != nil || len(m.Provenance.SourceURL) <= 0 {
+		return
+	}
+
+	for i := range m.Materials {
+		m.Materials[i].URL = toAbsURL(baseURL, m.Materials[i].URL)
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import "sort"
+
+// Ancestors は、指定したワーキンググループの親から最上位の会議体までのIDを、近い順に返すメソッドです。
+func (wgList WorkingGroupList) Ancestors(id string) []string {
+	ancestors := []string{}
+	visited := map[string]bool{id: true}
+
+	wg, exists := wgList[id]
+	for exists && len(wg.ParentID) > 0 && !visited[wg.ParentID] {
+		ancestors = append(ancestors, wg.ParentID)
+		visited[wg.ParentID] = true
+		wg, exists = wgList[wg.ParentID]
+	}
+
+	return ancestors
+}
+
+// RootID は、指定したワーキンググループが属する最上位の会議体のIDを返すメソッドです。
+func (wgList WorkingGroupList) RootID(id string) string {
+	ancestors := wgList.Ancestors(id)
+	if len(ancestors) <= 0 {
+		return id
+	}
+
+	return ancestors[len(ancestors)-1]
+}
+
+// Depth は、指定したワーキンググループの階層の深さを返すメソッドです。最上位の会議体は 0 です。
+func (wgList WorkingGroupList) Depth(id string) int {
+	return len(wgList.Ancestors(id))
+}
+
+// Children は、指定したワーキンググループの直下にある会議体のIDを返すメソッドです。
+func (wgList WorkingGroupList) Children(id string) []string {
+	children := []string{}
+	for childID, wg := range wgList {
+		if wg.ParentID == id && childID != id {
+			children = append(children, childID)
+		}
+	}
+	sort.Strings(children)
+
+	return children
+}
+
+// Descendants は、指定したワーキンググループより下位にあるすべての会議体のIDを返すメソッドです。
+func (wgList WorkingGroupList) Descendants(id string) []string {
+	descendants := []string{}
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, childID := range wgList.Children(current) {
+			if visited[childID] {
+				continue
+			}
+			visited[childID] = true
+			descendants = append(descendants, childID)
+			queue = append(queue, childID)
+		}
+	}
+
+	return descendants
+}
+
+// Subtree は、指定したワーキンググループとその下位にあるすべての会議体のIDの集合を返すメソッドです。
+func (wgList WorkingGroupList) Subtree(id string) map[string]bool {
+	subtree := map[string]bool{id: true}
+	for _, descendantID := range wgList.Descendants(id) {
+		subtree[descendantID] = true
+	}
+
+	return subtree
+}
+
+// FilterByWorkingGroup は、指定したワーキンググループとその下位の会議体の議事録だけを含む MinutesArray を返すメソッドです。
+func (minutesArray MinutesArray) FilterByWorkingGroup(wgList WorkingGroupList, id string) MinutesArray {
+	subtree := wgList.Subtree(id)
+
+	filtered := MinutesArray{}
+	for _, minutes := range minutesArray {
+		if subtree[minutes.WorkingGroupID] {
+			filtered = append(filtered, minutes)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// JSON Lines で書き出すファイルの名前です。
+const (
+	MinutesJSONLFileName = "all_minutes.jsonl"
+	SpeechJSONLFileName  = "all_speech.jsonl"
+)
+
+// JSONLWriter は、議事録単位と発言単位の JSON Lines ファイルを逐次書き出す構造体です。
+type JSONLWriter struct {
+	minutesFile    *os.File
+	speechFile     *os.File
+	minutesBuffer  *bufio.Writer
+	speechBuffer   *bufio.Writer
+	minutesEncoder *json.Encoder
+	speechEncoder  *json.Encoder
+}
+
+// NewJSONLWriter は、outputdir に all_minutes.jsonl と all_speech.jsonl を作成し、JSONLWriter を返す関数です。
+func NewJSONLWriter(outputdir string) (*JSONLWriter, error) {
+	minutesFile, err := os.Create(filepath.Join(outputdir, MinutesJSONLFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	speechFile, err := os.Create(filepath.Join(outputdir, SpeechJSONLFileName))
+	if err != nil {
+		minutesFile.Close()
+		return nil, err
+	}
+
+	w := &JSONLWriter{
+		minutesFile:   minutesFile,
+		speechFile:    speechFile,
+		minutesBuffer: bufio.NewWriter(minutesFile),
+		speechBuffer:  bufio.NewWriter(speechFile),
+	}
+	w.minutesEncoder = json.NewEncoder(w.minutesBuffer)
+	w.minutesEncoder.SetEscapeHTML(false)
+	w.speechEncoder = json.NewEncoder(w.speechBuffer)
+	w.speechEncoder.SetEscapeHTML(false)
+
+	return w, nil
+}
+
+// WriteMinutes は、議事録1件を all_minutes.jsonl に、その発言を all_speech.jsonl に1行ずつ書き出すメソッドです。
+// ImportMinutesArrayFromHTML に MinutesHandler として渡すことで、パースしながら書き出せます。
+func (w *JSONLWriter) WriteMinutes(minutes *Minutes) error {
+	if err := w.minutesEncoder.Encode(minutes); err != nil {
+		return err
+	}
+
+	for _, record := range minutes.SpeechRecords() {
+		if err := w.speechEncoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	// 途中で中断しても、書き出し済みの議事録は読み込めるようにする
+	if err := w.minutesBuffer.Flush(); err != nil {
+		return err
+	}
+	return w.speechBuffer.Flush()
+}
+
+// Close は、書き出し中のファイルを閉じるメソッドです。
+func (w *JSONLWriter) Close() error {
+	if err := w.minutesBuffer.Flush(); err != nil {
+		return err
+	}
+	if err := w.speechBuffer.Flush(); err != nil {
+		return err
+	}
+	if err := w.minutesFile.Close(); err != nil {
+		return err
+	}
+
+	return w.speechFile.Close()
+}
+
+// ScanMinutesJSONL は、議事録単位の JSON Lines ファイルを1行ずつ読み込み、議事録ごとに fn を呼び出す関数です。
+func ScanMinutesJSONL(path string, fn func(minutes Minutes) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for {
+		var minutes Minutes
+		if err := decoder.Decode(&minutes); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		minutes.relinkSpeakers()
+		if err := fn(minutes); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadMinutesJSONL は、議事録単位の JSON Lines ファイルを読み込んで MinutesArray を返す関数です。
+func ReadMinutesJSONL(path string) (MinutesArray, error) {
+	minutesArray := MinutesArray{}
+	err := ScanMinutesJSONL(path, func(minutes Minutes) error {
+		minutesArray = append(minutesArray, minutes)
+		return nil
+	})
+
+	return minutesArray, err
+}
+
+// ScanSpeechRecordsJSONL は、発言単位の JSON Lines ファイルを1行ずつ読み込み、発言ごとに fn を呼び出す関数です。
+func ScanSpeechRecordsJSONL(path string, fn func(record SpeechRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for {
+		var record SpeechRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// LoadMinutesArray は、parse コマンドの出力ディレクトリから MinutesArray を読み込む関数です。
+// all_minutes.jsonl があればそれを、なければ all.json を読み込みます。
+// tokens.jsonl があれば、形態素解析の結果も各発言に設定します。
+func LoadMinutesArray(dir string) (MinutesArray, error) {
+	minutesArray, err := loadMinutesArray(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tokensPath := filepath.Join(dir, TokensJSONLFileName)
+	if _, err := os.Stat(tokensPath); err == nil {
+		if err := minutesArray.AttachTokens(tokensPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return minutesArray, nil
+}
+
+// loadMinutesArray は、出力ディレクトリから議事録だけを読み込む関数です。
+func loadMinutesArray(dir string) (MinutesArray, error) {
+	jsonlPath := filepath.Join(dir, MinutesJSONLFileName)
+	if _, err := os.Stat(jsonlPath); err == nil {
+		return ReadMinutesJSONL(jsonlPath)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "all.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var minutesArray MinutesArray
+	if err := json.NewDecoder(file).Decode(&minutesArray); err != nil {
+		return nil, err
+	}
+	for i := range minutesArray {
+		minutesArray[i].relinkSpeakers()
+	}
+
+	return minutesArray, nil
+}
+
+// relinkSpeakers は、JSONから読み込んだ発言の Speaker を、Speakers に格納された同じラベルの Speaker に結び付け直すメソッドです。
+func (m *Minutes) relinkSpeakers() {
+	if m.Speakers == nil {
+		m.Speakers = map[string]*Speaker{}
+	}
+
+	for _, speach := range m.Speaches {
+		if speach == nil || speach.Speaker == nil {
+			continue
+		}
+
+		if speaker, exists := m.Speakers[speach.Speaker.Label]; exists {
+			speach.Speaker = speaker
+		} else {
+			m.Speakers[speach.Speaker.Label] = speach.Speaker
+		}
+	}
+}
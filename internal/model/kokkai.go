@@ -0,0 +1,484 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CouncilName は、議事録を取得する審議会の名称です。国会会議録形式の nameOfHouse に使用します。
+const CouncilName = "中央教育審議会"
+
+// kokkaiPersonNamespace は、国会会議録から取り込んだ話者の人物IDを生成するための UUID 名前空間です。
+var kokkaiPersonNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://kokkai.ndl.go.jp/person"))
+
+// KokkaiPersonID は、国会会議録の話者の氏名から人物IDを生成する関数です。
+// 審議会の人物とは別の名前空間で生成するため、同じ氏名の委員とは別の人物として扱われます。
+func KokkaiPersonID(name string) string {
+	return uuid.NewSHA1(kokkaiPersonNamespace, []byte(name)).String()
+}
+
+// 国会会議録形式で書き出すファイルの名前です。
+const (
+	KokkaiMeetingFileName = "kokkai_meeting.json"
+	KokkaiSpeechFileName  = "kokkai_speech.json"
+)
+
+// 国会会議録形式のJSONを読み込むパーサーの名称とバージョンです。
+const (
+	kokkaiParserName    = "ImportKokkaiJSON"
+	kokkaiParserVersion = "1.0"
+)
+
+// kokkaiHeaderSpeaker は、国会会議録の speechOrder 0 の発言（会議録の冒頭部分）に使われる話者名です。
+const kokkaiHeaderSpeaker = "会議録情報"
+
+// kokkaiSpeechPrefixPattern は、国会会議録の発言本文の先頭にある「○話者　」を取り出す正規表現です。
+var kokkaiSpeechPrefixPattern = regexp.MustCompile(`^[○◯]([^　\s]+)[　\s]*`)
+
+// KokkaiSpeechRecord は、国会会議録検索システムAPIの speechRecord に、文部科学省の議事録に固有の項目を加えた構造体です。
+type KokkaiSpeechRecord struct {
+	SpeechID        string  `json:"speechID"`
+	IssueID         string  `json:"issueID,omitempty"`
+	ImageKind       string  `json:"imageKind,omitempty"`
+	NameOfHouse     string  `json:"nameOfHouse,omitempty"`
+	NameOfMeeting   string  `json:"nameOfMeeting,omitempty"`
+	Issue           string  `json:"issue,omitempty"`
+	Date            string  `json:"date,omitempty"`
+	SpeechOrder     int     `json:"speechOrder"`
+	Speaker         string  `json:"speaker"`
+	SpeakerYomi     *string `json:"speakerYomi"`
+	SpeakerGroup    *string `json:"speakerGroup"`
+	SpeakerPosition *string `json:"speakerPosition"`
+	SpeakerRole     *string `json:"speakerRole"`
+	Speech          string  `json:"speech"`
+	StartPage       int     `json:"startPage"`
+	SpeechURL       string  `json:"speechURL,omitempty"`
+	MeetingURL      string  `json:"meetingURL,omitempty"`
+
+	// 以下は文部科学省の議事録に固有の項目です。
+	MeetingID       string   `json:"meetingID,omitempty"`
+	WorkingGroupID  string   `json:"workingGroupID,omitempty"`
+	Turn            *int     `json:"turn,omitempty"`
+	SpeakerLabel    string   `json:"speakerLabel,omitempty"`
+	PersonID        string   `json:"personID,omitempty"`
+	RoleClass       string   `json:"roleClass,omitempty"`
+	ResolutionScore *float64 `json:"resolutionScore,omitempty"`
+}
+
+// KokkaiMeetingRecord は、国会会議録検索システムAPIの meetingRecord に、文部科学省の議事録に固有の項目を加えた構造体です。
+type KokkaiMeetingRecord struct {
+	IssueID       string               `json:"issueID"`
+	ImageKind     string               `json:"imageKind"`
+	Session       *int                 `json:"session"`
+	NameOfHouse   string               `json:"nameOfHouse"`
+	NameOfMeeting string               `json:"nameOfMeeting"`
+	Issue         string               `json:"issue"`
+	Date          string               `json:"date"`
+	Closing       *string              `json:"closing"`
+	SpeechRecord  []KokkaiSpeechRecord `json:"speechRecord"`
+	MeetingURL    string               `json:"meetingURL"`
+	PdfURL        string               `json:"pdfURL,omitempty"`
+
+	// 以下は文部科学省の議事録に固有の項目です。
+	MeetingID      string     `json:"meetingID,omitempty"`
+	WorkingGroupID string     `json:"workingGroupID,omitempty"`
+	MeetingNumber  int        `json:"meetingNumber,omitempty"`
+	Title          string     `json:"title,omitempty"`
+	Venue          string     `json:"venue,omitempty"`
+	Topics         []string   `json:"topics,omitempty"`
+	Materials      []Material `json:"materials,omitempty"`
+}
+
+// KokkaiResponse は、国会会議録検索システムAPIの応答全体を表す構造体です。
+// 会議単位出力（meetingRecord）と発言単位出力（speechRecord）のどちらかを持ちます。
+type KokkaiResponse struct {
+	NumberOfRecords    int                   `json:"numberOfRecords"`
+	NumberOfReturn     int                   `json:"numberOfReturn"`
+	StartRecord        int                   `json:"startRecord"`
+	NextRecordPosition *int                  `json:"nextRecordPosition"`
+	MeetingRecord      []KokkaiMeetingRecord `json:"meetingRecord,omitempty"`
+	SpeechRecord       []KokkaiSpeechRecord  `json:"speechRecord,omitempty"`
+}
+
+// nullable は、空文字列を null として書き出すためのポインタに変換する関数です。
+func nullable(s string) *string {
+	if len(s) <= 0 {
+		return nil
+	}
+	return &s
+}
+
+// deref は、null の場合に空文字列を返す関数です。
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// KokkaiRecord は、議事録を国会会議録形式の meetingRecord に変換するメソッドです。
+// 冒頭の話者のない部分は speechOrder 0 の「会議録情報」とし、以降の発言に1から順に speechOrder を振ります。
+func (m Minutes) KokkaiRecord() KokkaiMeetingRecord {
+	issue := ""
+	if m.MeetingNumber > 0 {
+		issue = "第" + strconv.Itoa(m.MeetingNumber) + "回"
+	}
+
+	record := KokkaiMeetingRecord{
+		IssueID:        m.ID,
+		ImageKind:      "議事録",
+		NameOfHouse:    CouncilName,
+		NameOfMeeting:  m.WorkingGroup,
+		Issue:          issue,
+		Date:           m.Date,
+		MeetingURL:     m.Provenance.SourceURL,
+		MeetingID:      m.ID,
+		WorkingGroupID: m.WorkingGroupID,
+		MeetingNumber:  m.MeetingNumber,
+		Title:          m.Title,
+		Venue:          m.Venue,
+		Topics:         m.Topics,
+		Materials:      m.Materials,
+	}
+
+	newSpeechRecord := func(order int) KokkaiSpeechRecord {
+		return KokkaiSpeechRecord{
+			SpeechID:       fmt.Sprintf("%s_%03d", m.ID, order),
+			SpeechOrder:    order,
+			MeetingURL:     m.Provenance.SourceURL,
+			MeetingID:      m.ID,
+			WorkingGroupID: m.WorkingGroupID,
+		}
+	}
+
+	header := []string{m.Title}
+	for _, speach := range m.Speaches {
+		if speach == nil || speach.Speaker != nil {
+			break
+		}
+		header = append(header, speach.Talks...)
+	}
+	headerRecord := newSpeechRecord(0)
+	headerRecord.Speaker = kokkaiHeaderSpeaker
+	headerRecord.Speech = strings.Join(header, "\r\n")
+	record.SpeechRecord = append(record.SpeechRecord, headerRecord)
+
+	order := 0
+	for _, speach := range m.Speaches {
+		if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+			continue
+		}
+		order++
+
+		turn := speach.Turn
+		score := speach.Speaker.ResolutionScore
+		name := speach.Speaker.Person.Name
+		if len(name) <= 0 {
+			name = speach.Speaker.Label
+		}
+
+		talks := []string{}
+		for _, talk := range speach.Talks {
+			talks = append(talks, strings.TrimLeft(talk, "　"))
+		}
+
+		speechRecord := newSpeechRecord(order)
+		speechRecord.Speaker = name
+		speechRecord.SpeakerGroup = nullable(speach.Speaker.Person.Affiliation)
+		speechRecord.SpeakerPosition = nullable(speach.Speaker.Person.Role)
+		speechRecord.Speech = "○" + speach.Speaker.Label + "　" + strings.Join(talks, "\r\n　")
+		speechRecord.Turn = &turn
+		speechRecord.SpeakerLabel = speach.Speaker.Label
+		speechRecord.PersonID = speach.Speaker.Person.ID
+		speechRecord.RoleClass = RoleClass(speach.Speaker)
+		speechRecord.ResolutionScore = &score
+		record.SpeechRecord = append(record.SpeechRecord, speechRecord)
+	}
+
+	return record
+}
+
+// ExportAsKokkaiJSON は、MinutesArray を国会会議録検索システムAPIと互換の形式で書き出すメソッドです。
+// 会議単位出力の kokkai_meeting.json と、発言単位出力の kokkai_speech.json を作成します。
+func (minutesArray MinutesArray) ExportAsKokkaiJSON(outputdir string) error {
+	meetings := KokkaiResponse{StartRecord: 1}
+	speeches := KokkaiResponse{StartRecord: 1}
+
+	for _, minutes := range minutesArray {
+		record := minutes.KokkaiRecord()
+		meetings.MeetingRecord = append(meetings.MeetingRecord, record)
+
+		for _, speechRecord := range record.SpeechRecord {
+			if speechRecord.SpeechOrder == 0 {
+				continue
+			}
+			speechRecord.IssueID = record.IssueID
+			speechRecord.ImageKind = record.ImageKind
+			speechRecord.NameOfHouse = record.NameOfHouse
+			speechRecord.NameOfMeeting = record.NameOfMeeting
+			speechRecord.Issue = record.Issue
+			speechRecord.Date = record.Date
+			speeches.SpeechRecord = append(speeches.SpeechRecord, speechRecord)
+		}
+	}
+	meetings.NumberOfRecords = len(meetings.MeetingRecord)
+	meetings.NumberOfReturn = len(meetings.MeetingRecord)
+	speeches.NumberOfRecords = len(speeches.SpeechRecord)
+	speeches.NumberOfReturn = len(speeches.SpeechRecord)
+
+	if err := writeKokkaiResponse(filepath.Join(outputdir, KokkaiMeetingFileName), meetings); err != nil {
+		return err
+	}
+	return writeKokkaiResponse(filepath.Join(outputdir, KokkaiSpeechFileName), speeches)
+}
+
+// writeKokkaiResponse は、KokkaiResponse をJSONファイルに書き出す関数です。
+func writeKokkaiResponse(filePath string, response KokkaiResponse) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(response)
+}
+
+// ImportKokkaiJSON は、国会会議録検索システムAPIの応答を保存したJSONファイルを読み込んで MinutesArray を作成する関数です。
+// path にディレクトリを指定した場合は、その中の *.json をファイル名順にすべて読み込みます。
+// 会議単位出力・発言単位出力のどちらにも対応し、speechOrder 0 の「会議録情報」は発言として扱いません。
+// 発言単位出力は1回の応答が最大100件のため、複数のファイルにまたがる同じ issueID の発言は1つの会議にまとめます。
+func ImportKokkaiJSON(path string) (MinutesArray, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	meetings := []kokkaiImportedMeeting{}
+	index := map[string]int{}
+	add := func(meeting KokkaiMeetingRecord, provenance Provenance, fromMeetingRecord bool) {
+		i, exists := index[meeting.IssueID]
+		if !exists || len(meeting.IssueID) <= 0 {
+			index[meeting.IssueID] = len(meetings)
+			meetings = append(meetings, kokkaiImportedMeeting{record: meeting, provenance: provenance, fromMeetingRecord: fromMeetingRecord})
+			return
+		}
+		meetings[i].merge(meeting, fromMeetingRecord)
+	}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var response KokkaiResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			return nil, err
+		}
+
+		provenance := newProvenance(file, content, kokkaiParserName, kokkaiParserVersion)
+		for _, meeting := range response.MeetingRecord {
+			add(meeting, provenance, true)
+		}
+		for _, meeting := range groupKokkaiSpeechRecords(response.SpeechRecord) {
+			add(meeting, provenance, false)
+		}
+	}
+
+	minutesArray := MinutesArray{}
+	for _, meeting := range meetings {
+		meeting.record.SpeechRecord = sortKokkaiSpeechRecords(meeting.record.SpeechRecord)
+
+		minutes := minutesFromKokkaiRecord(meeting.record)
+		minutes.Provenance = meeting.provenance
+		minutes.Provenance.SourceURL = meeting.record.MeetingURL
+		minutesArray = append(minutesArray, minutes)
+	}
+
+	return minutesArray, nil
+}
+
+// kokkaiImportedMeeting は、読み込み中の会議と、その会議を最初に読み込んだファイルの来歴をまとめた構造体です。
+type kokkaiImportedMeeting struct {
+	record            KokkaiMeetingRecord
+	provenance        Provenance
+	fromMeetingRecord bool
+}
+
+// merge は、同じ issueID の会議を読み込んだ際に、発言を追加するメソッドです。
+// 会議単位出力の項目は発言単位出力から作成したものより詳しいため、会議単位出力があればその項目を使います。
+func (m *kokkaiImportedMeeting) merge(meeting KokkaiMeetingRecord, fromMeetingRecord bool) {
+	speechRecords := append(m.record.SpeechRecord, meeting.SpeechRecord...)
+	if fromMeetingRecord && !m.fromMeetingRecord {
+		m.record = meeting
+		m.fromMeetingRecord = true
+	}
+	m.record.SpeechRecord = speechRecords
+}
+
+// groupKokkaiSpeechRecords は、発言単位出力の speechRecord を issueID ごとに会議単位の meetingRecord にまとめる関数です。
+func groupKokkaiSpeechRecords(speechRecords []KokkaiSpeechRecord) []KokkaiMeetingRecord {
+	meetings := []KokkaiMeetingRecord{}
+	index := map[string]int{}
+
+	for _, speechRecord := range speechRecords {
+		i, exists := index[speechRecord.IssueID]
+		if !exists {
+			i = len(meetings)
+			index[speechRecord.IssueID] = i
+			meetings = append(meetings, KokkaiMeetingRecord{
+				IssueID:        speechRecord.IssueID,
+				ImageKind:      speechRecord.ImageKind,
+				NameOfHouse:    speechRecord.NameOfHouse,
+				NameOfMeeting:  speechRecord.NameOfMeeting,
+				Issue:          speechRecord.Issue,
+				Date:           speechRecord.Date,
+				MeetingURL:     speechRecord.MeetingURL,
+				MeetingID:      speechRecord.MeetingID,
+				WorkingGroupID: speechRecord.WorkingGroupID,
+			})
+		}
+		meetings[i].SpeechRecord = append(meetings[i].SpeechRecord, speechRecord)
+	}
+
+	return meetings
+}
+
+// sortKokkaiSpeechRecords は、speechRecord を speechOrder の順に並べ、同じ speechOrder の重複を取り除く関数です。
+// 同じ発言が会議単位出力と発言単位出力の両方に含まれる場合は、先に読み込んだものを残します。
+func sortKokkaiSpeechRecords(speechRecords []KokkaiSpeechRecord) []KokkaiSpeechRecord {
+	sort.SliceStable(speechRecords, func(a, b int) bool {
+		return speechRecords[a].SpeechOrder < speechRecords[b].SpeechOrder
+	})
+
+	sorted := []KokkaiSpeechRecord{}
+	for i, speechRecord := range speechRecords {
+		if i > 0 && speechRecord.SpeechOrder == speechRecords[i-1].SpeechOrder {
+			continue
+		}
+		sorted = append(sorted, speechRecord)
+	}
+
+	return sorted
+}
+
+// KokkaiWorkingGroupID は、国会の院名と会議名からワーキンググループIDを作成する関数です。
+// 同じ会議名からは常に同じIDが得られます。
+func KokkaiWorkingGroupID(nameOfHouse string, nameOfMeeting string) string {
+	sum := sha1.Sum([]byte(nameOfHouse + "/" + nameOfMeeting))
+	return "kokkai_" + hex.EncodeToString(sum[:4])
+}
+
+// minutesFromKokkaiRecord は、meetingRecord を Minutes に変換する関数です。
+// 文部科学省の議事録に固有の項目があればそれを優先し、なければ国会会議録の項目から補います。
+func minutesFromKokkaiRecord(meeting KokkaiMeetingRecord) Minutes {
+	minutes := Minutes{
+		ID:             meeting.MeetingID,
+		MeetingNumber:  meeting.MeetingNumber,
+		Title:          meeting.Title,
+		WorkingGroup:   meeting.NameOfMeeting,
+		WorkingGroupID: meeting.WorkingGroupID,
+		Date:           meeting.Date,
+		Venue:          meeting.Venue,
+		Topics:         meeting.Topics,
+		Materials:      meeting.Materials,
+		Speakers:       map[string]*Speaker{},
+		Speaches:       []*Speach{},
+	}
+
+	if len(minutes.ID) <= 0 {
+		minutes.ID = meeting.IssueID
+	}
+	if len(minutes.WorkingGroupID) <= 0 {
+		minutes.WorkingGroupID = KokkaiWorkingGroupID(meeting.NameOfHouse, meeting.NameOfMeeting)
+	}
+	if minutes.MeetingNumber <= 0 {
+		if number, ok := ParseJapaneseNumber(strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(meeting.Issue, "第"), "号"), "回")); ok {
+			minutes.MeetingNumber = number
+		}
+	}
+	if len(minutes.Title) <= 0 {
+		minutes.Title = strings.TrimSpace(strings.Join([]string{meeting.NameOfHouse, meeting.NameOfMeeting, meeting.Issue}, " "))
+	}
+
+	for _, speechRecord := range meeting.SpeechRecord {
+		if speechRecord.SpeechOrder == 0 {
+			continue
+		}
+
+		speech := strings.Replace(speechRecord.Speech, "\r\n", "\n", -1)
+		label := speechRecord.SpeakerLabel
+		if match := kokkaiSpeechPrefixPattern.FindStringSubmatch(speech); match != nil {
+			if len(label) <= 0 {
+				label = match[1]
+			}
+			speech = speech[len(match[0]):]
+		}
+		if len(label) <= 0 {
+			label = speechRecord.Speaker + deref(speechRecord.SpeakerPosition)
+		}
+
+		speaker, exists := minutes.Speakers[label]
+		if !exists {
+			speaker = &Speaker{Label: label}
+			if len(speechRecord.SpeakerLabel) > 0 {
+				// meroku が書き出したデータは、名寄せの結果をそのまま復元する
+				if len(speechRecord.PersonID) > 0 {
+					speaker.Person = Person{ID: speechRecord.PersonID, Name: speechRecord.Speaker, Role: deref(speechRecord.SpeakerPosition), Affiliation: deref(speechRecord.SpeakerGroup)}
+				}
+				if speechRecord.ResolutionScore != nil {
+					speaker.ResolutionScore = *speechRecord.ResolutionScore
+				}
+			} else {
+				// 国会会議録の話者は氏名が明記されているため、名寄せ済みとして扱う
+				speaker.Person = Person{
+					ID:          KokkaiPersonID(speechRecord.Speaker),
+					Label:       label,
+					Name:        speechRecord.Speaker,
+					Role:        deref(speechRecord.SpeakerPosition),
+					Affiliation: deref(speechRecord.SpeakerGroup),
+				}
+				speaker.ResolutionScore = 1.0
+			}
+			minutes.Speakers[label] = speaker
+		}
+
+		talks := []string{}
+		for _, line := range strings.Split(speech, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(line, "　"))
+			if len(line) > 0 {
+				talks = append(talks, line)
+			}
+		}
+
+		turn := speechRecord.SpeechOrder
+		if speechRecord.Turn != nil {
+			turn = *speechRecord.Turn
+		}
+		minutes.Speaches = append(minutes.Speaches, &Speach{MeetingID: minutes.ID, Turn: turn, Speaker: speaker, Talks: talks})
+	}
+	minutes.SpeachCount = len(minutes.Speaches)
+	minutes.SegmentAgenda()
+
+	return minutes
+}
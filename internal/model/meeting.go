@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// meetingNumberPattern は、会議名に含まれる「第N回」を検出する正規表現です。
+var meetingNumberPattern = regexp.MustCompile(`第[\s　]*([0-9０-９〇零一二三四五六七八九十百千]+)[\s　]*回`)
+
+// meetingTermPattern は、会議名に含まれる「第N期」を検出する正規表現です。
+var meetingTermPattern = regexp.MustCompile(`第[\s　]*([0-9０-９〇零一二三四五六七八九十百千]+)[\s　]*期`)
+
+// kanjiDigits は、漢数字と数値の対応表です。
+var kanjiDigits = map[rune]int{
+	'〇': 0, '零': 0, '一': 1, '二': 2, '三': 3, '四': 4,
+	'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// kanjiUnits は、漢数字の位取りと数値の対応表です。
+var kanjiUnits = map[rune]int{
+	'十': 10, '百': 100, '千': 1000,
+}
+
+// ParseJapaneseNumber は、半角・全角の算用数字や漢数字で書かれた数を整数に変換する関数です。
+// 「十二」のような位取りのある表記と、「一二」のような位取りのない表記の両方に対応しています。
+func ParseJapaneseNumber(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) <= 0 {
+		return 0, false
+	}
+
+	total := 0
+	current := 0
+
+	for _, r := range s {
+		digit, isKanjiDigit := kanjiDigits[r]
+		switch {
+		case r >= '0' && r <= '9':
+			current = current*10 + int(r-'0')
+		case r >= '０' && r <= '９':
+			current = current*10 + int(r-'０')
+		case isKanjiDigit:
+			current = current*10 + digit
+		case kanjiUnits[r] > 0:
+			if current == 0 {
+				current = 1
+			}
+			total += current * kanjiUnits[r]
+			current = 0
+		default:
+			return 0, false
+		}
+	}
+
+	return total + current, true
+}
+
+// ParseMeetingNumber は、会議名から「第N回」の回数を抽出する関数です。見つからない場合は false を返します。
+func ParseMeetingNumber(title string) (int, bool) {
+	matches := meetingNumberPattern.FindStringSubmatch(title)
+	if len(matches) < 2 {
+		return 0, false
+	}
+
+	return ParseJapaneseNumber(matches[1])
+}
+
+// ParseMeetingTerm は、会議名から「第N期」の期数を抽出する関数です。見つからない場合は false を返します。
+// 部会などは期が替わると回数を第1回から数え直すため、会議IDには期数も含めます。
+func ParseMeetingTerm(title string) (int, bool) {
+	matches := meetingTermPattern.FindStringSubmatch(title)
+	if len(matches) < 2 {
+		return 0, false
+	}
+
+	return ParseJapaneseNumber(matches[1])
+}
+
+// MeetingID は、ワーキンググループIDと期数・回数から会議の安定したIDを作成する関数です。
+// 期数が 0 の場合（会議名に期がない場合）は、期数を含めずにIDを作成します。
+func MeetingID(wgID string, term int, number int) string {
+	if term > 0 {
+		return fmt.Sprintf("wg%s-t%02d-%03d", wgID, term, number)
+	}
+
+	return fmt.Sprintf("wg%s-%03d", wgID, number)
+}
+
+// assignIdentity は、会議名とファイル名から会議の期数・回数とIDを決定し、各発言にも会議IDと発言順を書き込むメソッドです。
+// 回数が読み取れない場合は、取得元ファイル名（拡張子を除く）をIDに用います。
+func (m *Minutes) assignIdentity(fileName string) {
+	number, ok := ParseMeetingNumber(m.Title)
+	if ok {
+		m.MeetingNumber = number
+		m.Term, _ = ParseMeetingTerm(m.Title)
+		m.ID = MeetingID(m.WorkingGroupID, m.Term, number)
+	} else {
+		base := filepath.Base(fileName)
+		base = base[:len(base)-len(filepath.Ext(base))]
+		if idx := strings.Index(base, "-"); idx >= 0 {
+			base = base[idx+1:]
+		}
+		m.ID = "wg" + m.WorkingGroupID + "-" + base
+	}
+
+	for turn, speach := range m.Speaches {
+		speach.MeetingID = m.ID
+		speach.Turn = turn
+	}
+}
+
+// meetingIDSet は、すでに割り当てた会議IDを記録し、重複したIDを区別できるようにする型です。
+type meetingIDSet map[string]bool
+
+// disambiguate は、m の会議IDがすでに使われている場合に「-2」「-3」…の連番を付けて重複しないIDに変更するメソッドです。
+// 同じ会議が html と html_from_pdf の両方にある場合なども、警告を出力したうえで別の会議として扱います。
+func (ids meetingIDSet) disambiguate(m *Minutes) {
+	id := m.ID
+	for n := 2; ids[id]; n++ {
+		id = fmt.Sprintf("%s-%d", m.ID, n)
+	}
+
+	if id != m.ID {
+		log.Printf("WARN: %v: 会議ID %v が重複しているため、%v に変更します。\n", m.Provenance.LocalPath, m.ID, id)
+		m.ID = id
+		for _, speach := range m.Speaches {
+			speach.MeetingID = id
+		}
+	}
+	ids[id] = true
+}
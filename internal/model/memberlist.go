@@ -1,15 +1,17 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
-	"os"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/google/uuid"
@@ -25,6 +27,15 @@ type Person struct {
 	Role        string
 }
 
+// personNamespace は、名簿に由来しない人物のIDを生成するための UUID 名前空間です。
+var personNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/tsunekawa/meroku/person"))
+
+// PersonID は、文字列から人物のIDを生成する関数です。同じ文字列からは常に同じIDが生成されます。
+// 名簿の委員のIDには使いません（同姓同名の別人を同じ人物とみなさないよう、名簿の委員には無作為なIDを割り当てます）。
+func PersonID(name string) string {
+	return uuid.NewSHA1(personNamespace, []byte(name)).String()
+}
+
 // NormarizeLabel は、Personの属性からラベルを生成するためのメソッドです。
 func (person Person) NormarizeLabel() (label string) {
 	return strings.Join([]string{person.Name, person.Role}, "")
@@ -34,8 +45,12 @@ func (person Person) NormarizeLabel() (label string) {
 type MemberList struct {
 	WorkingGroup *WorkingGroup
 	Members      []*Person
+	Provenance   Provenance
 }
 
+// memberListParserVersion は、名簿パーサーのバージョンです。
+const memberListParserVersion = "1.0"
+
 // ToJSON は、MemberList型のデータをJSON形式の文字列として返すメソッドです。
 func (m MemberList) ToJSON() string {
 	jsondata, _ := json.MarshalIndent(m, "", "    ")
@@ -149,14 +164,16 @@ func ParseMemberListFromHTML(reader io.Reader) (m MemberList, err error) {
 
 // LoadMemberListFromHTML は、引数として与えられたファイルパスから名簿HTMLファイルを開いてパースし、MemberListを返すメソッドです。
 func LoadMemberListFromHTML(filepath string) (memberList MemberList, err error) {
-	var reader *os.File
-	reader, err = os.Open(filepath)
-	defer reader.Close()
+	content, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return memberList, err
+	}
 
-	memberList, err = ParseMemberListFromHTML(reader)
+	memberList, err = ParseMemberListFromHTML(bytes.NewReader(content))
 	if err != nil {
 		log.Fatal(err)
 	}
+	memberList.Provenance = newProvenance(filepath, content, "ParseMemberListFromHTML", memberListParserVersion)
 
 	return memberList, err
 }
@@ -172,13 +189,48 @@ func LoadMemberListFromURL(url string) (memberList MemberList, err error) {
 	if err != nil {
 		return memberList, err
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode >= 400 {
 		err := errors.New(url + " : " + response.Status)
 		return memberList, err
 	}
 
-	memberList, err = ParseMemberListFromHTML(response.Body)
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return memberList, err
+	}
+
+	memberList, err = ParseMemberListFromHTML(bytes.NewReader(content))
+	memberList.Provenance = newProvenance("", content, "ParseMemberListFromHTML", memberListParserVersion)
+	memberList.Provenance.SourceURL = url
+	memberList.Provenance.FetchedAt = time.Now().Format(time.RFC3339)
 
 	return memberList, err
-}
\ No newline at end of file
+}
+
+// MemberListMap は、ワーキンググループIDをキーとする名簿のマップです。
+type MemberListMap map[string]*MemberList
+
+// ResolveSpeakers は、議事録の話者をそのワーキンググループの名簿と名寄せし、Speaker に Person を格納するメソッドです。
+// ImportMinutesArrayFromHTML に MinutesHandler として渡すことで、パースと同時に名寄せを行えます。
+func (memberListMap MemberListMap) ResolveSpeakers(minutes *Minutes) error {
+	memberlist, exists := memberListMap[minutes.WorkingGroupID]
+	if !exists {
+		return nil
+	}
+
+	for _, speaker := range minutes.Speakers {
+		person, sims, err := memberlist.Resolve(speaker.Label)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		speaker.Person = *person
+		speaker.ResolutionScore = sims[0].Score
+		log.Println("名寄せ：" + speaker.Label + "(" + speaker.Person.ID + ")")
+	}
+
+	return nil
+}
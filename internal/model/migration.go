@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// legacyFileNamePattern は、表示順を含む旧形式のファイル名（noXXwgYYY-）を検出する正規表現です。
+var legacyFileNamePattern = regexp.MustCompile(`^no[0-9]{2}(wg[0-9]{3}-.+)$`)
+
+// wgFileNamePattern は、ファイル名からワーキンググループIDを抽出する正規表現です。
+var wgFileNamePattern = regexp.MustCompile(`^(?:no[0-9]{2})?wg([0-9A-Za-z_]+?)-`)
+
+// WorkingGroupIDFromFileName は、ダウンロードしたファイルの名前からワーキンググループIDを取り出す関数です。
+// 旧形式（noXXwgYYY-）と新形式（wgYYY-）のどちらにも対応しています。
+func WorkingGroupIDFromFileName(fileName string) (string, bool) {
+	matches := wgFileNamePattern.FindStringSubmatch(filepath.Base(fileName))
+	if len(matches) < 2 {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+// canonicalFileName は、旧形式のファイル名から表示順の接頭辞を取り除いた名前を返す関数です。
+func canonicalFileName(fileName string) string {
+	base := filepath.Base(fileName)
+	if matches := legacyFileNamePattern.FindStringSubmatch(base); len(matches) >= 2 {
+		return matches[1]
+	}
+
+	return base
+}
+
+// isLegacy は、WorkingGroupList が表示順をキーとする旧形式かどうかを判定するメソッドです。
+func (wgList WorkingGroupList) isLegacy() bool {
+	for key, wg := range wgList {
+		if key != wg.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rekeyByID は、WorkingGroupList をIDをキーとするマップに変換して返すメソッドです。
+// IDが記録されていない項目は、URLからIDを抽出し直します。URLからもIDを抽出できない項目は、警告を出力して除きます。
+func (wgList WorkingGroupList) rekeyByID() WorkingGroupList {
+	if !wgList.isLegacy() {
+		return wgList
+	}
+
+	rekeyed := WorkingGroupList{}
+	for key, wg := range wgList {
+		if len(wg.ID) <= 0 {
+			wg.GetIDFromURL()
+		}
+		if len(wg.ID) <= 0 {
+			log.Printf("WARN: IDを取得できないワーキンググループを除きます : 「%v」(%v)\n", wg.Name, key)
+			continue
+		}
+		rekeyed[wg.ID] = wg
+	}
+
+	return rekeyed
+}
+
+// MigrateLegacyDownloadDir は、旧形式のダウンロードディレクトリを新形式に移行する関数です。
+// working-groups.json をIDをキーとする形式に書き換え、noXXwgYYY- で始まるファイルを wgYYY- で始まる名前に変更します。
+func MigrateLegacyDownloadDir(rootDir string) error {
+	wgListPath := filepath.Join(rootDir, "working-groups.json")
+	if raw, err := ioutil.ReadFile(wgListPath); err == nil {
+		var wgList WorkingGroupList
+		if err := json.Unmarshal(raw, &wgList); err != nil {
+			return err
+		}
+
+		if wgList.isLegacy() {
+			if err := wgList.rekeyByID().Save(wgListPath); err != nil {
+				return err
+			}
+			log.Println("移行: " + wgListPath + " をIDをキーとする形式に変換しました。")
+		}
+	}
+
+	dirs := []string{
+		filepath.Join(rootDir, "html"),
+		filepath.Join(rootDir, "html", "memberlist"),
+		filepath.Join(rootDir, "html_from_pdf"),
+	}
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+
+			newName := canonicalFileName(file.Name())
+			if newName == file.Name() {
+				continue
+			}
+
+			oldPath := filepath.Join(dir, file.Name())
+			newPath := filepath.Join(dir, newName)
+			if _, err := os.Stat(newPath); err == nil {
+				log.Printf("WARN: %v は既に存在するため、%v の名前を変更しませんでした。\n", newPath, oldPath)
+				continue
+			}
+			if err := os.Rename(oldPath, newPath); err != nil {
+				return err
+			}
+			log.Println("移行: " + oldPath + " → " + newName)
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,7 @@
 package model
 
 import (
-	"encoding/csv"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -14,8 +12,6 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-	"golang.org/x/text/encoding/japanese"
-	"golang.org/x/text/transform"
 )
 
 // Speaker は議事録に出現する話者を表現するための構造体です。
@@ -27,24 +23,40 @@ type Speaker struct {
 
 // Speach is ...
 type Speach struct {
-	Speaker *Speaker
-	Talks   []string
+	MeetingID  string
+	Turn       int
+	Speaker    *Speaker
+	Talks      []string
+	Tokens     []Token `json:"-"`          // 形態素解析の結果（tokens.jsonl に別に保存する）
+	AgendaItem int     `json:",omitempty"` // 発言が扱っている議題の番号（SegmentAgenda で設定する）
 }
 
 // Minutes is ...
 type Minutes struct {
-	Title             string
-	WorkingGroup      string
-	SpeachCount       int
-	WorkingGroupOrder string
-	WorkingGroupID    string
-	Date              string
-	Venue             string
-	Topics            []string
-	Speakers          map[string]*Speaker
-	Speaches          []*Speach
+	ID             string
+	MeetingNumber  int
+	Term           int `json:",omitempty"` // 会議名に含まれる「第N期」の期数
+	Title          string
+	WorkingGroup   string
+	SpeachCount    int
+	WorkingGroupID string
+	Date           string
+	Venue          string
+	Topics         []string
+	Speakers       map[string]*Speaker
+	Speaches       []*Speach
+	Materials      []Material
+	Provenance     Provenance
 }
 
+// パーサーの名称とバージョンです。出力結果に影響する変更を加えた場合はバージョンを上げてください。
+const (
+	htmlParserName        = "ParseMinutesFromFile"
+	htmlParserVersion     = "1.0"
+	pdf2htmlParserName    = "ParseMinutesFromPDF2Html"
+	pdf2htmlParserVersion = "1.0"
+)
+
 // ToJSON は、Minutes型のデータをJSON形式の文字列として返すメソッドです。
 func (m Minutes) ToJSON() string {
 	jsondata, _ := json.MarshalIndent(m, "", "    ")
@@ -62,20 +74,19 @@ func ParseMinutesFromFile(fileName string) Minutes {
 	doc, _ := goquery.NewDocumentFromReader(reader)
 
 	minutes := Minutes{
-		Title:    doc.Find("h1").Text(),
-		Speaches: []*Speach{},
-		Speakers: map[string]*Speaker{},
+		Title:      doc.Find("h1").Text(),
+		Speaches:   []*Speach{},
+		Speakers:   map[string]*Speaker{},
+		Provenance: newProvenance(fileName, file, htmlParserName, htmlParserVersion),
 	}
 
-	wginfotag := regexp.MustCompile(`no([0-9][0-9])wg([0-9][0-9][0-9])-.+htm`)
-	wginfo := wginfotag.FindStringSubmatch(fileName)
-	if len(wginfo) >= 3 {
-		minutes.WorkingGroupOrder = wginfo[1]
-		minutes.WorkingGroupID = wginfo[2]
-	}
+	minutes.WorkingGroupID, _ = WorkingGroupIDFromFileName(fileName)
 
 	minutes.WorkingGroup = strings.Split(minutes.Title, "　")[0]
 
+	minutes.parseHeader(doc)
+	minutes.parseMaterials(doc)
+
 	currentSpeach := new(Speach)
 
 	doc.Find(QUERY).Each(func(index int, s *goquery.Selection) {
@@ -111,6 +122,9 @@ func ParseMinutesFromFile(fileName string) Minutes {
 
 	minutes.SpeachCount = len(minutes.Speaches)
 
+	minutes.assignIdentity(fileName)
+	minutes.SegmentAgenda()
+
 	return minutes
 }
 
@@ -137,25 +151,20 @@ func ParseMinutesFromPDF2Html(fileName string) Minutes {
 	})
 
 	minutes := Minutes{
-		Title:    kaigiTitle,
-		Speaches: []*Speach{},
-		Speakers: map[string]*Speaker{},
+		Title:      kaigiTitle,
+		Speaches:   []*Speach{},
+		Speakers:   map[string]*Speaker{},
+		Provenance: newProvenance(fileName, file, pdf2htmlParserName, pdf2htmlParserVersion),
 	}
 
-	wginfotag := regexp.MustCompile(`no([0-9][0-9])wg([0-9][0-9][0-9])-.+htm`)
-	wginfo := wginfotag.FindStringSubmatch(fileName)
-	if len(wginfo) >= 3 {
-		minutes.WorkingGroupOrder = wginfo[1]
-		minutes.WorkingGroupID = wginfo[2]
-	}
+	minutes.WorkingGroupID, _ = WorkingGroupIDFromFileName(fileName)
 
 	minutes.WorkingGroup = strings.Split(minutes.Title, "（")[0]
 
+	minutes.parseHeader(doc)
+
 	//発話中のhtmlタグを除去するんだな…（Acrobatが勝手にアンダーラインとかも再現しちゃうので）
 	reptag := regexp.MustCompile(`<("[^"]*"|'[^']*'|[^'">])*>`)
-	//行の途中でページを跨いじゃってることを検知する正規表現
-	kutentag := regexp.MustCompile(`[^。）―─]$`)
-
 	currentSpeach := new(Speach)
 
 	speakerDefined := false
@@ -189,8 +198,7 @@ func ParseMinutesFromPDF2Html(fileName string) Minutes {
 			if len(talk) > 0 {
 				if speakerDefined {
 					//改ページ位置に跨って文中で分離してしまっている箇所をさがす
-					bunmatsu := kutentag.FindString(talk)
-					if bunmatsu != "" {
+					if ContinuesOnNextLine(talk) {
 						//log.Print("FF Found!!: " + bunmatsu)
 						prevTalk = talk
 					} else {
@@ -208,6 +216,9 @@ func ParseMinutesFromPDF2Html(fileName string) Minutes {
 
 	minutes.SpeachCount = len(minutes.Speaches)
 
+	minutes.assignIdentity(fileName)
+	minutes.SegmentAgenda()
+
 	return minutes
 
 }
@@ -227,85 +238,143 @@ func (minutesArray MinutesArray) ExportAsJSON(outputdir string) {
 	fp2.WriteString(string(json2data))
 }
 
-//ExportSpeakersAsCSV は、CSV形式で発話者リストを書き出すメソッドです。
-func (minutesArray MinutesArray) ExportSpeakersAsCSV(outputdir string) {
+// ExportSpeakersAsCSV は、CSV形式で発話者リストを書き出すメソッドです。
+// 文字コードは encoding で指定します。表現できない文字は「〓」に置き換えてログに出力し、その一覧を返します。
+func (minutesArray MinutesArray) ExportSpeakersAsCSV(outputdir string, encoding CSVEncoding) ([]EncodingIssue, error) {
 	// O_WRONLY:書き込みモード開く, O_CREATE:無かったらファイルを作成
 	filePath3 := filepath.Join(outputdir, "all_speaker.csv")
 	file3, err := os.OpenFile(filePath3, os.O_WRONLY|os.O_CREATE, 0777)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file3.Close()
 
 	err = file3.Truncate(0) // ファイルを空っぽにする(2回目以降用)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	writer := csv.NewWriter(transform.NewWriter(file3, japanese.ShiftJIS.NewEncoder()))
-	writer.Write([]string{"WorkingGroupOrder", "WorkingGroupID", "Title", "Speaker.Label", "Speaker.ResolutionScore", "Person.ID", "Person.Label", "Person.Name", "Person.Role", "Person.Affiliation"})
+	writer, err := NewCSVWriter(file3, encoding)
+	if err != nil {
+		return nil, err
+	}
+	writer.Write([]string{"MeetingID", "MeetingNumber", "WorkingGroupID", "Title", "Speaker.Label", "Speaker.ResolutionScore", "Person.ID", "Person.Label", "Person.Name", "Person.Role", "Person.Affiliation"})
 	for _, v := range minutesArray {
 		for _, sp := range v.Speakers {
-		    score := strconv.FormatFloat(sp.ResolutionScore, 'g', -1, 32)
-			writer.Write([]string{v.WorkingGroupOrder, v.WorkingGroupID, v.Title, sp.Label, score, sp.Person.ID, sp.Person.Label, sp.Person.Name, sp.Person.Role, sp.Person.Affiliation})
+			score := strconv.FormatFloat(sp.ResolutionScore, 'g', -1, 32)
+			writer.Write([]string{v.ID, strconv.Itoa(v.MeetingNumber), v.WorkingGroupID, v.Title, sp.Label, score, sp.Person.ID, sp.Person.Label, sp.Person.Name, sp.Person.Role, sp.Person.Affiliation})
 		}
 	}
 
-	writer.Flush()
+	if err := writer.Flush(); err != nil {
+		return writer.Issues, err
+	}
+	writer.ReportIssues(filePath3)
+
+	return writer.Issues, nil
 }
 
+// KHVariablesFileName は、KH Coder の外部変数として読み込むファイルの名前です。
+const KHVariablesFileName = "all_khcoder_variables.csv"
+
 // ExportAsKH は、KH Coder読み込み用のファイルをエクスポートするメソッドです。
-func (minutesArray MinutesArray) ExportAsKH(wgList WorkingGroupList, outputdir string) (bool, error) {
+// 本文（all_khcoder.txt）とあわせて、<h3>の単位ごとに1行の外部変数ファイル（all_khcoder_variables.csv）を書き出します。
+// 本文と外部変数ファイルはKH Coderがあわせて読み込むため、どちらも encoding で指定した文字コードで書き出します。wgList にないワーキンググループは、警告を出力したうえでIDを見出しにします。
+func (minutesArray MinutesArray) ExportAsKH(wgList WorkingGroupList, outputdir string, encoding CSVEncoding) ([]EncodingIssue, error) {
 	lines := []string{}
+	variables := [][]string{}
 
-	crWgOrder := ""
+	crWgID := ""
 	for _, v := range minutesArray {
-		if crWgOrder != v.WorkingGroupOrder {
-			wg, exists := wgList["no"+v.WorkingGroupOrder]
+		if crWgID != v.WorkingGroupID {
+			wg, exists := wgList[v.WorkingGroupID]
 			if (exists) {
-				lines = append(lines, "<h1>"+wg.Name+"</h1>\n")
+				lines = append(lines, "<h1>"+wg.Name+"</h1>")
 			} else {
-				return false, errors.New(v.WorkingGroupOrder + "という番号のWGが見つかりません。")
+				log.Printf("WARN: %vというIDのWGが見つからないため、IDを見出しにします。\n", v.WorkingGroupID)
+				lines = append(lines, "<h1>"+v.WorkingGroupID+"</h1>")
 			}
 		}
-		crWgOrder = v.WorkingGroupOrder
+		crWgID = v.WorkingGroupID
 
 		if (len(v.Title) > 0) {
-			lines = append(lines, "<h2>"+v.Title+"</h2>\n")
+			lines = append(lines, "<h2>"+v.Title+"</h2>")
 		}
 
 		for _, sp := range v.Speaches {
-			if sp.Speaker == nil {
-				sp.Speaker = &Speaker{}
+			speaker := sp.Speaker
+			if speaker == nil {
+				speaker = &Speaker{}
 			}
-			lines = append(lines, "<h3>"+sp.Speaker.Label+"</h3>\n")
+			lines = append(lines, "<h3>"+speaker.Label+"</h3>")
 			for _, tk := range sp.Talks {
-				lines = append(lines, tk+"\n")
+				lines = append(lines, tk)
+			}
+
+			roleClass := ""
+			if sp.Speaker != nil {
+				roleClass = RoleClass(sp.Speaker)
 			}
+			variables = append(variables, []string{speaker.Label, speaker.Person.ID, roleClass, speaker.Person.Affiliation, v.Date, v.WorkingGroupID, v.ID})
 		}
 	}
 
 	filenameKH := filepath.Join(outputdir, "all_khcoder.txt")
 	fileKH, err := os.Create(filenameKH)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer fileKH.Close()
 
+	textWriter, err := NewTextWriter(fileKH, encoding)
+	if err != nil {
+		return nil, err
+	}
 	for _, line := range lines {
-		_, err := fileKH.WriteString(line)
-		if err != nil {
-			log.Fatal(err)
+		if err := textWriter.WriteLine(line); err != nil {
+			return textWriter.Issues, err
 		}
 	}
 
-	return true, nil
+	if err := textWriter.Flush(); err != nil {
+		return textWriter.Issues, err
+	}
+	textWriter.ReportIssues(filenameKH)
+
+	filenameVariables := filepath.Join(outputdir, KHVariablesFileName)
+	fileVariables, err := os.Create(filenameVariables)
+	if err != nil {
+		return nil, err
+	}
+	defer fileVariables.Close()
+
+	writer, err := NewCSVWriter(fileVariables, encoding)
+	if err != nil {
+		return nil, err
+	}
+	writer.Write([]string{"speaker_label", "person_id", "role_class", "affiliation", "date", "wg_id", "meeting_id"})
+	for _, record := range variables {
+		writer.Write(record)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return append(textWriter.Issues, writer.Issues...), err
+	}
+	writer.ReportIssues(filenameVariables)
+
+	return append(textWriter.Issues, writer.Issues...), nil
 }
 
+// MinutesHandler は、パースした議事録ごとに実行される処理を表す関数型です。
+type MinutesHandler func(minutes *Minutes) error
+
 // ImportMinutesArrayFromHTML は、複数のHTMLファイルを読み込んで MinutesArray を作成する関数です。
-func ImportMinutesArrayFromHTML(baseDirs []string, outputDir string) MinutesArray {
+// sources に記録されている取得元の情報は、各 Minutes の Provenance に書き込まれます。
+// handlers は、議事録をパースするたびに、個別のJSONファイルを書き出す前に順に実行されます。
+func ImportMinutesArrayFromHTML(baseDirs []string, outputDir string, sources SourceIndex, handlers ...MinutesHandler) MinutesArray {
 	var minutesArray MinutesArray
 	var pdfFlag bool
+	meetingIDs := meetingIDSet{}
 
 	for num, baseDir := range baseDirs {
 
@@ -329,6 +398,15 @@ func ImportMinutesArrayFromHTML(baseDirs []string, outputDir string) MinutesArra
 				fmt.Println("Processing: " + file.Name())
 				m = ParseMinutesFromFile(baseDir + "/" + file.Name())
 			}
+			sources.Apply(&m.Provenance)
+			m.resolveMaterialURLs()
+			meetingIDs.disambiguate(&m)
+
+			for _, handler := range handlers {
+				if err := handler(&m); err != nil {
+					log.Fatal(err)
+				}
+			}
 
 			filePath := filepath.Join(outputDir, file.Name()+".json")
 			fp, err := os.Create(filePath)
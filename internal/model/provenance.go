@@ -0,0 +1,82 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tsunekawa/meroku/internal/downloader"
+)
+
+// MerokuVersion は、実行中の meroku のバージョンです。main パッケージが VERSION ファイルの内容で上書きします。
+var MerokuVersion = "unknown"
+
+// Provenance は、パース結果がどの入力からどのように作られたかを記録する構造体です。
+type Provenance struct {
+	SourceURL     string
+	LocalPath     string
+	FetchedAt     string
+	SHA256        string
+	Parser        string
+	ParserVersion string
+	MerokuVersion string
+}
+
+// newProvenance は、ローカルファイルの内容とパーサー情報から Provenance を作成する関数です。
+func newProvenance(localPath string, content []byte, parser string, parserVersion string) Provenance {
+	sum := sha256.Sum256(content)
+
+	return Provenance{
+		LocalPath:     localPath,
+		SHA256:        hex.EncodeToString(sum[:]),
+		Parser:        parser,
+		ParserVersion: parserVersion,
+		MerokuVersion: MerokuVersion,
+	}
+}
+
+// SourceIndex は、ダウンロード済みファイル名（表示順の接頭辞を除いたもの）から取得記録を引くための索引です。
+type SourceIndex map[string]downloader.FetchRecord
+
+// LoadSourceIndex は、ダウンロードディレクトリのレポートとワーキンググループ一覧から SourceIndex を作成する関数です。
+// レポートに記録のないファイルは、ワーキンググループ一覧のURLとファイル名を突き合わせて補完します。
+func LoadSourceIndex(rootDir string, wgList WorkingGroupList) SourceIndex {
+	index := SourceIndex{}
+
+	for _, wg := range wgList {
+		prefix := wg.FilePrefix()
+		for _, u := range append(append([]string{}, wg.MinutesURLs...), wg.MemberListURLs...) {
+			index[prefix+filepath.Base(u)] = downloader.FetchRecord{URL: u}
+		}
+	}
+
+	reports, err := downloader.LoadReports(rootDir)
+	if err != nil {
+		return index
+	}
+	for _, report := range reports {
+		for _, record := range report.Records {
+			index[canonicalFileName(record.Path)] = record
+		}
+	}
+
+	return index
+}
+
+// Apply は、索引から取得元URLと取得日時を探して Provenance に書き込むメソッドです。
+// 取得日時が記録されていないダウンロード済みファイルは、ファイルの更新日時で代用します。
+func (index SourceIndex) Apply(p *Provenance) {
+	record, exists := index[canonicalFileName(p.LocalPath)]
+	if !exists {
+		return
+	}
+
+	p.SourceURL = record.URL
+	if !record.FetchedAt.IsZero() {
+		p.FetchedAt = record.FetchedAt.Format(time.RFC3339)
+	} else if info, err := os.Stat(p.LocalPath); err == nil {
+		p.FetchedAt = info.ModTime().Format(time.RFC3339)
+	}
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 話者の役割の分類です。
+const (
+	RoleClassMember      = "member"
+	RoleClassSecretariat = "secretariat"
+	RoleClassOther       = "other"
+)
+
+// secretariatKeywords は、事務局（文部科学省の職員など）の話者ラベルに含まれる語です。
+var secretariatKeywords = []string{
+	"事務局", "大臣", "副大臣", "政務官", "次官", "審議官", "官房", "局長", "部長", "課長", "室長",
+	"企画官", "調査官", "専門官", "参事官", "補佐", "係長", "統括官",
+}
+
+// memberKeywords は、委員の話者ラベルに含まれる語です。
+var memberKeywords = []string{
+	"委員", "主査", "座長", "会長", "部会長", "分科会長",
+}
+
+// RoleClass は、話者ラベルと名寄せの結果から、話者を委員 (member)・事務局 (secretariat)・その他 (other) に分類する関数です。
+// 事務局を示す語を含むラベルは、名寄せの結果にかかわらず事務局に分類します。
+func RoleClass(speaker *Speaker) string {
+	if speaker == nil {
+		return RoleClassOther
+	}
+
+	for _, keyword := range secretariatKeywords {
+		if strings.Contains(speaker.Label, keyword) {
+			return RoleClassSecretariat
+		}
+	}
+
+	if len(speaker.Person.ID) > 0 {
+		return RoleClassMember
+	}
+
+	for _, keyword := range memberKeywords {
+		if strings.Contains(speaker.Label, keyword) {
+			return RoleClassMember
+		}
+	}
+
+	return RoleClassOther
+}
+
+// sentenceTerminators は、文末とみなす記号です。
+const sentenceTerminators = "。？！?!"
+
+// SplitSentences は、文章を「。？！」で文に分割する関数です。文末記号の直後の閉じ括弧は前の文に含めます。
+func SplitSentences(text string) []string {
+	sentences := []string{}
+	runes := []rune(text)
+
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(sentenceTerminators, runes[i]) {
+			continue
+		}
+		for i+1 < len(runes) && (strings.ContainsRune(sentenceTerminators, runes[i+1]) || strings.ContainsRune("」』）)", runes[i+1])) {
+			i++
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); len(sentence) > 0 {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+
+	if sentence := strings.TrimSpace(string(runes[start:])); len(sentence) > 0 {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// lineEndings は、行末にあれば文や段落がそこで完結しているとみなす文字です。
+const lineEndings = "。）―─"
+
+// ContinuesOnNextLine は、段落が文の途中で終わっており、次の段落に続いているかを判定する関数です。
+// PDFから変換した議事録では、改ページの位置で文が分断されることがあるため、この判定で段落をつなぎます。
+func ContinuesOnNextLine(talk string) bool {
+	talk = strings.TrimSpace(talk)
+	if len(talk) <= 0 {
+		return false
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(talk)
+	return !strings.ContainsRune(lineEndings, last)
+}
+
+// CountCharacters は、空白文字を除いた文字数を返す関数です。
+func CountCharacters(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Text は、発言を構成する段落を改行でつないだ文字列を返すメソッドです。
+func (speach Speach) Text() string {
+	talks := []string{}
+	for _, talk := range speach.Talks {
+		talks = append(talks, strings.TrimSpace(talk))
+	}
+
+	return strings.Join(talks, "\n")
+}
+
+// SpeechRecord は、発言1件を1行とする表形式（tidy data）の行を表す構造体です。
+type SpeechRecord struct {
+	MeetingID      string
+	WorkingGroupID string
+	Date           string
+	Turn           int
+	SpeakerLabel   string
+	PersonID       string
+	RoleClass      string
+	Text           string
+	CharCount      int
+	SentenceCount  int
+	AgendaItem     int `json:",omitempty"`
+}
+
+// SpeechRecords は、議事録の発言を SpeechRecord の配列として返すメソッドです。
+// 話者が特定されていない発言（冒頭の開催情報など）と、本文が空の発言は含みません。
+func (m Minutes) SpeechRecords() []SpeechRecord {
+	records := []SpeechRecord{}
+
+	for _, speach := range m.Speaches {
+		if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+			continue
+		}
+
+		text := speach.Text()
+		records = append(records, SpeechRecord{
+			MeetingID:      m.ID,
+			WorkingGroupID: m.WorkingGroupID,
+			Date:           m.Date,
+			Turn:           speach.Turn,
+			SpeakerLabel:   speach.Speaker.Label,
+			PersonID:       speach.Speaker.Person.ID,
+			RoleClass:      RoleClass(speach.Speaker),
+			Text:           text,
+			CharCount:      CountCharacters(text),
+			SentenceCount:  len(SplitSentences(text)),
+			AgendaItem:     speach.AgendaItem,
+		})
+	}
+
+	return records
+}
+
+// SpeechRecords は、すべての議事録の発言を SpeechRecord の配列として返すメソッドです。
+func (minutesArray MinutesArray) SpeechRecords() []SpeechRecord {
+	records := []SpeechRecord{}
+	for _, minutes := range minutesArray {
+		records = append(records, minutes.SpeechRecords()...)
+	}
+
+	return records
+}
+
+// speechTableHeader は、発言単位の表の列名です。
+var speechTableHeader = []string{"meeting_id", "wg_id", "date", "turn", "speaker_label", "person_id", "role_class", "text", "char_count", "sentence_count"}
+
+// toRow は、SpeechRecord を表の1行分の文字列の配列に変換するメソッドです。
+func (record SpeechRecord) toRow() []string {
+	return []string{
+		record.MeetingID,
+		record.WorkingGroupID,
+		record.Date,
+		strconv.Itoa(record.Turn),
+		record.SpeakerLabel,
+		record.PersonID,
+		record.RoleClass,
+		record.Text,
+		strconv.Itoa(record.CharCount),
+		strconv.Itoa(record.SentenceCount),
+	}
+}
+
+// ExportSpeechesAsTable は、発言1件を1行とする表を UTF-8 の CSV (format = "csv") または TSV (format = "tsv") で書き出すメソッドです。
+// TSV では、本文中のタブと改行を空白に置き換えます。
+func (minutesArray MinutesArray) ExportSpeechesAsTable(outputdir string, format string) error {
+	var delimiter rune
+	switch format {
+	case "csv":
+		delimiter = ','
+	case "tsv":
+		delimiter = '\t'
+	default:
+		return errors.New(format + "は対応していない形式です。(csv, tsv)")
+	}
+
+	filePath := filepath.Join(outputdir, "all_speech."+format)
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer, err := NewCSVWriter(file, EncodingUTF8)
+	if err != nil {
+		return err
+	}
+	writer.SetDelimiter(delimiter)
+
+	writer.Write(speechTableHeader)
+	for _, record := range minutesArray.SpeechRecords() {
+		if format == "tsv" {
+			record.Text = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(record.Text)
+		}
+		writer.Write(record.toRow())
+	}
+
+	return writer.Flush()
+}
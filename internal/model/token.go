@@ -0,0 +1,132 @@
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// TokensJSONLFileName は、形態素解析の結果をキャッシュする JSON Lines ファイルの名前です。
+const TokensJSONLFileName = "tokens.jsonl"
+
+// Token は、形態素解析で得られた1語分の情報を表す構造体です。
+// POS は品詞・品詞細分類を「-」でつないだもの（例: 名詞-固有名詞-組織）です。
+type Token struct {
+	Surface  string
+	BaseForm string
+	POS      string
+	Reading  string
+}
+
+// TokenRecord は、発言1件分の形態素解析の結果を tokens.jsonl の1行として表す構造体です。
+type TokenRecord struct {
+	MeetingID string
+	Turn      int
+	Tokens    []Token
+}
+
+// TokenWriter は、発言ごとの形態素解析の結果を tokens.jsonl に逐次書き出す構造体です。
+type TokenWriter struct {
+	file    *os.File
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewTokenWriter は、outputdir に tokens.jsonl を作成し、TokenWriter を返す関数です。
+func NewTokenWriter(outputdir string) (*TokenWriter, error) {
+	file, err := os.Create(filepath.Join(outputdir, TokensJSONLFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &TokenWriter{file: file, buffer: bufio.NewWriter(file)}
+	w.encoder = json.NewEncoder(w.buffer)
+	w.encoder.SetEscapeHTML(false)
+
+	return w, nil
+}
+
+// WriteMinutes は、議事録に含まれる解析済みの発言を1件ずつ書き出すメソッドです。
+// ImportMinutesArrayFromHTML に MinutesHandler として、形態素解析の後に渡してください。
+func (w *TokenWriter) WriteMinutes(minutes *Minutes) error {
+	for _, speach := range minutes.Speaches {
+		if speach == nil || speach.Tokens == nil {
+			continue
+		}
+
+		if err := w.encoder.Encode(TokenRecord{MeetingID: minutes.ID, Turn: speach.Turn, Tokens: speach.Tokens}); err != nil {
+			return err
+		}
+	}
+
+	return w.buffer.Flush()
+}
+
+// Close は、書き出し中のファイルを閉じるメソッドです。
+func (w *TokenWriter) Close() error {
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Close()
+}
+
+// ScanTokensJSONL は、tokens.jsonl を1行ずつ読み込み、発言ごとに fn を呼び出す関数です。
+func ScanTokensJSONL(path string, fn func(record TokenRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for {
+		var record TokenRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// AttachTokens は、tokens.jsonl に記録された形態素解析の結果を、会議IDと発言番号が一致する発言の Tokens に設定するメソッドです。
+func (minutesArray MinutesArray) AttachTokens(path string) error {
+	speaches := map[string]map[int]*Speach{}
+	for _, minutes := range minutesArray {
+		if _, exists := speaches[minutes.ID]; !exists {
+			speaches[minutes.ID] = map[int]*Speach{}
+		}
+		for _, speach := range minutes.Speaches {
+			if speach != nil {
+				speaches[minutes.ID][speach.Turn] = speach
+			}
+		}
+	}
+
+	return ScanTokensJSONL(path, func(record TokenRecord) error {
+		if speach, exists := speaches[record.MeetingID][record.Turn]; exists {
+			speach.Tokens = record.Tokens
+		}
+		return nil
+	})
+}
+
+// HasTokens は、形態素解析の結果を持つ発言が1件以上あるかを返すメソッドです。
+func (minutesArray MinutesArray) HasTokens() bool {
+	for _, minutes := range minutesArray {
+		for _, speach := range minutes.Speaches {
+			if speach != nil && speach.Tokens != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
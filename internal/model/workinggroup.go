@@ -8,8 +8,10 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/tsunekawa/meroku/internal/downloader"
@@ -24,19 +26,36 @@ type WorkingGroup struct {
 	MinutesListURL string
 	MinutesURLs    []string
 	MemberListURLs []string
+	ParentID       string
+	ChildIDs       []string
+	Status         string
+	ActiveFrom     string
+	ActiveUntil    string
 }
 
+// ワーキンググループの状態を表す値です。
+const (
+	StatusActive = "active"
+	StatusClosed = "closed"
+)
+
 // GetIDFromURL は、ワーキンググループ情報のURLからIDを抽出するメソッドです。実行するとIDメンバーに結果が格納されます。
+// chukyo3 以下にない会議体（過去の審議会など）は、/b_menu/shingi/ 以下のパスからIDを作成します（例: chukyo_chukyo11）。
 func (wg *WorkingGroup) GetIDFromURL() string {
 	reg := regexp.MustCompile(`chukyo3/(\d{3})/index.htm`)
+	pathReg := regexp.MustCompile(`/b_menu/shingi/(.+)/index\.html?`)
 	if len(wg.URL) <= 0 {
 		wg.ID = ""
 	}
 
 	results := reg.FindAllStringSubmatch(wg.URL, -1)
 
+	pathResults := pathReg.FindStringSubmatch(wg.URL)
+
 	if len(results) > 0 {
 		wg.ID = results[0][1]
+	} else if len(pathResults) > 1 {
+		wg.ID = regexp.MustCompile(`[^0-9A-Za-z]+`).ReplaceAllString(pathResults[1], "_")
 	} else {
 		wg.ID = ""
 	}
@@ -44,6 +63,11 @@ func (wg *WorkingGroup) GetIDFromURL() string {
 	return wg.ID
 }
 
+// FilePrefix は、ダウンロードしたファイルの名前に付ける接頭辞（wgNNN-）を返すメソッドです。
+func (wg WorkingGroup) FilePrefix() string {
+	return "wg" + wg.ID + "-"
+}
+
 // GetMinutesListURL は、議事録一覧ページのURLをワーキンググループのページから抽出するメソッドです。実行すると MinutesListURLメンバーに値が格納されます。
 func (wg *WorkingGroup) GetMinutesListURL() (string, error) {
 	doc, err := goquery.NewDocument(wg.URL)
@@ -51,7 +75,16 @@ func (wg *WorkingGroup) GetMinutesListURL() (string, error) {
 		return "", err
 	}
 
+	return wg.minutesListURLFromPage(doc)
+}
+
+// minutesListURLFromPage は、取得済みのワーキンググループのページから議事録一覧ページのURLを抽出するメソッドです。
+func (wg *WorkingGroup) minutesListURLFromPage(doc *goquery.Document) (string, error) {
+	// 過去の会議体のページでは、一覧へのリンクの文言が異なることがある
 	nodes := doc.Find("a:contains('これまでの議事要旨・議事録・配付資料の一覧はこちら')")
+	if nodes.Length() <= 0 {
+		nodes = doc.Find("a:contains('議事要旨・議事録・配付資料')")
+	}
 	if nodes.Length() <= 0 {
 		err := errors.New("議事録一覧のリンクがありません : " + wg.URL)
 		return "", err
@@ -76,9 +109,17 @@ func (wg *WorkingGroup) GetMinutesList() ([]string, error) {
 		return gijiList, err
 	}
 
+	return wg.fetchMinutesList(minutesURL)
+}
+
+// fetchMinutesList は、議事録一覧ページから議事録のURLの一覧を取得し、MinutesURLs に配列として格納するメソッドです。
+// 一覧ページを取得できない場合はエラーを返し、クロール全体は中断しません。
+func (wg *WorkingGroup) fetchMinutesList(minutesURL string) ([]string, error) {
+	gijiList := []string{}
+
 	doc, err := goquery.NewDocument(minutesURL)
 	if err != nil {
-		log.Fatalf("%v : WG %v", err, wg.ID)
+		return gijiList, fmt.Errorf("%v : WG %v", err, wg.ID)
 	}
 	doc.Find("a:contains('議事録')").Each(func(idx int, node *goquery.Selection) {
 		if node.Text() == "議事録" {
@@ -104,6 +145,11 @@ func (wg *WorkingGroup) GetMemberListURLs() (memberListURLs []string, err error)
 		return memberListURLs, err
 	}
 
+	return wg.memberListURLsFromPage(doc)
+}
+
+// memberListURLsFromPage は、取得済みのワーキンググループのページから名簿ページのURLを抽出するメソッドです。
+func (wg *WorkingGroup) memberListURLsFromPage(doc *goquery.Document) (memberListURLs []string, err error) {
 	nodes := doc.Find("a:contains('委員名簿')")
 	if nodes.Length() <= 0 {
 		err := errors.New("名簿のリンクがありません : " + wg.URL)
@@ -128,13 +174,14 @@ func (wg *WorkingGroup) GetMemberListURLs() (memberListURLs []string, err error)
 func (wg WorkingGroup) DownloadMinutesAll(datadir string) (downloader.DownloadReport, error) {
 	downloadedURLs := []string{}
 	errorURLs := []string{}
+	records := []downloader.FetchRecord{}
 	minutesList, err := wg.GetMinutesList()
 	if err != nil {
 		return downloader.DownloadReport{}, err
 	}
 
 	for _, minutesURL := range minutesList {
-		fileName := wg.Order + "wg" + wg.ID + "-" + regexp.MustCompile(`[^/]+$`).FindString(minutesURL)
+		fileName := wg.FilePrefix() + regexp.MustCompile(`[^/]+$`).FindString(minutesURL)
 		//dir := filepath.Join(datadir, wg.Order)
 		dir := filepath.Join(datadir, "html")
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -144,10 +191,11 @@ func (wg WorkingGroup) DownloadMinutesAll(datadir string) (downloader.DownloadRe
 			}
 		}
 		filePath := dir + "/" + fileName
-		success, _ := downloader.Download(minutesURL, filePath)
+		record, err := downloader.Fetch(minutesURL, filePath)
 
-		if success {
+		if err == nil {
 			downloadedURLs = append(downloadedURLs, minutesURL)
+			records = append(records, record)
 		} else {
 			errorURLs = append(errorURLs, minutesURL)
 		}
@@ -155,6 +203,7 @@ func (wg WorkingGroup) DownloadMinutesAll(datadir string) (downloader.DownloadRe
 	return downloader.DownloadReport{
 		DownloadedList: downloadedURLs,
 		ErrorList:      errorURLs,
+		Records:        records,
 	}, nil
 }
 
@@ -162,13 +211,14 @@ func (wg WorkingGroup) DownloadMinutesAll(datadir string) (downloader.DownloadRe
 func (wg WorkingGroup) DownloadMemberListAll(datadir string) (downloader.DownloadReport, error) {
 	downloadedURLs := []string{}
 	errorURLs := []string{}
+	records := []downloader.FetchRecord{}
 	memberListList, err := wg.GetMemberListURLs()
 	if err != nil {
 		return downloader.DownloadReport{}, err
 	}
 
 	for _, memberListURL := range memberListList {
-		fileName := wg.Order + "wg" + wg.ID + "-" + regexp.MustCompile(`[^/]+$`).FindString(memberListURL)
+		fileName := wg.FilePrefix() + regexp.MustCompile(`[^/]+$`).FindString(memberListURL)
 		//dir := filepath.Join(datadir, wg.Order)
 		dir := filepath.Join(datadir, "html", "memberlist")
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -178,10 +228,11 @@ func (wg WorkingGroup) DownloadMemberListAll(datadir string) (downloader.Downloa
 			}
 		}
 		filePath := dir + "/" + fileName
-		success, _ := downloader.Download(memberListURL, filePath)
+		record, err := downloader.Fetch(memberListURL, filePath)
 
-		if success {
+		if err == nil {
 			downloadedURLs = append(downloadedURLs, memberListURL)
+			records = append(records, record)
 		} else {
 			errorURLs = append(errorURLs, memberListURL)
 		}
@@ -192,15 +243,33 @@ func (wg WorkingGroup) DownloadMemberListAll(datadir string) (downloader.Downloa
 	return downloader.DownloadReport{
 		DownloadedList: downloadedURLs,
 		ErrorList:      errorURLs,
+		Records:        records,
 	}, err
 }
 
-// GetWorkingGroups は、中央教育審議会のページからワーキンググループの一覧情報を抽出し、配列して返却するメソッドです。
+// rootURL は、中央教育審議会の会議体一覧ページのURLです。
+const rootURL = "https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/index.htm"
+
+// GetWorkingGroups は、中央教育審議会のページからワーキンググループの一覧情報を抽出し、IDをキーとするマップとして返却するメソッドです。
+// Order には一覧ページ上の表示順が記録されますが、ページの更新で変わるためキーには使用しません。
+// 一覧に掲載された会議体のページから下位の会議体（特別部会・ワーキンググループなど）もたどり、ParentID に親のIDを記録します。
 func GetWorkingGroups() map[string]*WorkingGroup {
-	const rootURL = "https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/index.htm"
-	doc, _ := goquery.NewDocument(rootURL)
+	return CrawlWorkingGroups(rootURL)
+}
 
+// CrawlWorkingGroups は、indexURL の会議体一覧ページに掲載された会議体と、それらのページからたどれる下位の会議体を抽出し、IDをキーとするマップとして返却する関数です。
+// 一覧ページに掲載された会議体は最上位の会議体とし、他の会議体のページにリンクがあっても下位の会議体にはしません。
+func CrawlWorkingGroups(indexURL string) map[string]*WorkingGroup {
 	workingGroups := map[string]*WorkingGroup{}
+	queue := []*WorkingGroup{}
+
+	doc, err := goquery.NewDocument(indexURL)
+	if err != nil {
+		log.Printf("WARN: 会議体一覧の取得失敗 : %v\n", indexURL)
+		log.Println(err)
+		return workingGroups
+	}
+	baseURL, _ := url.Parse(indexURL)
 
 	doc.Find(".shingi_block ul li a").Each(func(idx int, node *goquery.Selection) {
 		wg := new(WorkingGroup)
@@ -208,29 +277,247 @@ func GetWorkingGroups() map[string]*WorkingGroup {
 
 		href, exists := node.Attr("href")
 		if exists {
-			wg.URL = "https://www.mext.go.jp" + href
+			wg.URL = toAbsURL(baseURL, href)
+		}
+
+		if len(wg.GetIDFromURL()) <= 0 {
+			log.Printf("WARN: IDを取得できないワーキンググループを除きます : 「%v」(%v)\n", wg.Name, wg.URL)
+			return
+		}
+		if _, exists := workingGroups[wg.ID]; exists {
+			return
+		}
+
+		wg.Order = fmt.Sprintf("no%02d", idx)
+		wg.Status = StatusActive
+
+		workingGroups[wg.ID] = wg
+		queue = append(queue, wg)
+	})
+
+	crawlChildWorkingGroups(workingGroups, queue)
+
+	return workingGroups
+}
+
+// GetArchivedWorkingGroups は、中央教育審議会の「過去の」で始まる一覧ページ（過去の審議会・部会など）から、設置期間の終了した会議体を抽出する関数です。
+// archiveURLs に一覧ページのURLを指定した場合は、中央教育審議会のページからの探索に加えてそれらのページも読み込みます。
+func GetArchivedWorkingGroups(archiveURLs []string) map[string]*WorkingGroup {
+	return CrawlArchivedWorkingGroups(rootURL, archiveURLs)
+}
+
+// CrawlArchivedWorkingGroups は、indexURL の会議体一覧ページからリンクされた「過去の」で始まる一覧ページと archiveURLs の一覧ページから、設置期間の終了した会議体を抽出する関数です。
+// 一覧ページの項目（li 要素）のリンクのうち、indexURL と同じ審議会のディレクトリ（indexURL の1つ上の階層）にあり、indexURL に現在の会議体として掲載されていないものを対象にします。
+// 返却される会議体の Status は closed となり、一覧に設置期間が記載されていれば ActiveFrom, ActiveUntil に記録されます。
+func CrawlArchivedWorkingGroups(indexURL string, archiveURLs []string) map[string]*WorkingGroup {
+	workingGroups := map[string]*WorkingGroup{}
+	queue := []*WorkingGroup{}
+
+	baseURL, err := url.Parse(indexURL)
+	if err != nil {
+		log.Printf("WARN: 会議体一覧のURLが不正です : %v\n", indexURL)
+		log.Println(err)
+		return workingGroups
+	}
+	councilPath := path.Dir(path.Dir(baseURL.Path)) + "/"
+
+	listURLs := append([]string{}, archiveURLs...)
+	current := map[string]bool{}
+	if doc, err := goquery.NewDocument(indexURL); err == nil {
+		doc.Find("a:contains('過去の')").Each(func(idx int, node *goquery.Selection) {
+			href, exists := node.Attr("href")
+			if exists {
+				listURLs = append(listURLs, toAbsURL(baseURL, href))
+			}
+		})
+		doc.Find(".shingi_block ul li a").Each(func(idx int, node *goquery.Selection) {
+			href, _ := node.Attr("href")
+			wg := &WorkingGroup{URL: toAbsURL(baseURL, href)}
+			if id := wg.GetIDFromURL(); len(id) > 0 {
+				current[id] = true
+			}
+		})
+	} else {
+		log.Printf("WARN: 会議体一覧の取得失敗 : %v\n", indexURL)
+		log.Println(err)
+	}
+
+	visitedLists := map[string]bool{}
+	for _, listURL := range listURLs {
+		if visitedLists[listURL] {
+			continue
 		}
+		visitedLists[listURL] = true
 
-		wg.GetIDFromURL()
-		_, err := wg.GetMinutesList()
+		doc, err := goquery.NewDocument(listURL)
 		if err != nil {
-			log.Printf("WARN: 議事録一覧の取得失敗 : 「%v」(%v)\n", wg.Name, wg.ID)
+			log.Printf("WARN: 過去の会議体一覧の取得失敗 : %v\n", listURL)
 			log.Println(err)
+			continue
 		}
+		listBaseURL, _ := url.Parse(listURL)
+
+		doc.Find("#contentsMain li a").Each(func(idx int, node *goquery.Selection) {
+			href, exists := node.Attr("href")
+			if !exists {
+				return
+			}
+
+			wg := new(WorkingGroup)
+			wg.Name = strings.TrimSpace(node.Text())
+			wg.URL = toAbsURL(listBaseURL, href)
+			if wg.URL == listURL || wg.URL == indexURL || len(wg.GetIDFromURL()) <= 0 {
+				return
+			}
+
+			// 他の審議会の会議体や、現在の会議体へのリンクは除く
+			linkURL, err := url.Parse(wg.URL)
+			if err != nil || linkURL.Host != baseURL.Host || !strings.HasPrefix(linkURL.Path, councilPath) || current[wg.ID] {
+				return
+			}
+			if _, exists := workingGroups[wg.ID]; exists {
+				return
+			}
 
-		_, err = wg.GetMemberListURLs()
+			// 一覧の項目に併記された設置期間を読み取る
+			wg.ActiveFrom, wg.ActiveUntil = ParsePeriod(node.Parent().Text())
+			wg.Status = StatusClosed
+			wg.Order = fmt.Sprintf("archive%02d", len(workingGroups))
+
+			workingGroups[wg.ID] = wg
+			queue = append(queue, wg)
+		})
+	}
+
+	crawlChildWorkingGroups(workingGroups, queue)
+
+	return workingGroups
+}
+
+// crawlChildWorkingGroups は、queue に含まれる会議体のページを取得して議事録一覧・名簿一覧・設置期間を読み取り、ページに掲載された下位の会議体をたどって workingGroups に追加する関数です。
+// 会議体のページは1回だけ取得し、同じ文書からすべての項目を読み取ります。下位の会議体の Status は親の Status を引き継ぎます。
+// すでに workingGroups にある会議体（一覧ページに掲載された会議体や、先に他の会議体のページで見つかった会議体）へのリンクは関連する会議体へのリンクとみなし、親を付け替えません。
+func crawlChildWorkingGroups(workingGroups map[string]*WorkingGroup, queue []*WorkingGroup) {
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		doc, err := goquery.NewDocument(parent.URL)
 		if err != nil {
-			log.Printf("WARN: 名簿一覧の取得失敗 : 「%v」(%v)\n", wg.Name, wg.ID)
+			log.Printf("WARN: 会議体のページの取得失敗 : 「%v」(%v)\n", parent.Name, parent.ID)
+			log.Println(err)
+			continue
+		}
+		parent.collectLists(doc)
+
+		for idx, child := range parent.childWorkingGroupsFromPage(doc) {
+			if _, exists := workingGroups[child.ID]; exists {
+				continue
+			}
+
+			child.Order = fmt.Sprintf("%v-%02d", parent.Order, idx)
+			child.ParentID = parent.ID
+			child.Status = parent.Status
+			parent.ChildIDs = append(parent.ChildIDs, child.ID)
+
+			workingGroups[child.ID] = child
+			queue = append(queue, child)
+		}
+	}
+}
+
+// collectLists は、取得済みのワーキンググループのページから議事録一覧・名簿一覧・設置期間を読み取るメソッドです。取得に失敗した場合は警告を出力します。
+func (wg *WorkingGroup) collectLists(doc *goquery.Document) {
+	minutesListURL, err := wg.minutesListURLFromPage(doc)
+	if err == nil {
+		_, err = wg.fetchMinutesList(minutesListURL)
+	}
+	if err != nil {
+		log.Printf("WARN: 議事録一覧の取得失敗 : 「%v」(%v)\n", wg.Name, wg.ID)
+		log.Println(err)
+	}
+
+	_, err = wg.memberListURLsFromPage(doc)
+	if err != nil {
+		log.Printf("WARN: 名簿一覧の取得失敗 : 「%v」(%v)\n", wg.Name, wg.ID)
+		log.Println(err)
+	}
+
+	if len(wg.ActiveFrom) <= 0 {
+		if _, _, err = wg.periodFromPage(doc); err != nil {
+			log.Printf("WARN: 設置期間の取得失敗 : 「%v」(%v)\n", wg.Name, wg.ID)
 			log.Println(err)
 		}
+	}
+}
 
-		dispOrder := fmt.Sprintf("no%02d", idx)
-		wg.Order = dispOrder
+// GetPeriod は、ワーキンググループのページに記載された設置期間を抽出するメソッドです。実行すると ActiveFrom, ActiveUntil メンバーに値が格納されます。
+// 設置期間の記載がない場合は、どちらも空文字列のままとなります。
+func (wg *WorkingGroup) GetPeriod() (string, string, error) {
+	doc, err := goquery.NewDocument(wg.URL)
+	if err != nil {
+		return "", "", err
+	}
 
-		workingGroups[dispOrder] = wg
+	return wg.periodFromPage(doc)
+}
+
+// periodFromPage は、取得済みのワーキンググループのページから設置期間を抽出するメソッドです。
+func (wg *WorkingGroup) periodFromPage(doc *goquery.Document) (string, string, error) {
+	text := doc.Find("#contentsMain").Text()
+	for _, keyword := range []string{"設置期間", "審議期間", "設置期限"} {
+		if idx := strings.Index(text, keyword); idx >= 0 {
+			// 設置期間の記載の直後だけを対象にする
+			window := []rune(text[idx:])
+			if len(window) > 60 {
+				window = window[:60]
+			}
+			wg.ActiveFrom, wg.ActiveUntil = ParsePeriod(string(window))
+			break
+		}
+	}
+
+	return wg.ActiveFrom, wg.ActiveUntil, nil
+}
+
+// GetChildWorkingGroups は、ワーキンググループのページに掲載されている下位の会議体を抽出するメソッドです。
+// 返却される WorkingGroup には Name, URL, ID のみが格納されます。
+func (wg *WorkingGroup) GetChildWorkingGroups() ([]*WorkingGroup, error) {
+	doc, err := goquery.NewDocument(wg.URL)
+	if err != nil {
+		return []*WorkingGroup{}, err
+	}
+
+	return wg.childWorkingGroupsFromPage(doc), nil
+}
+
+// childWorkingGroupsFromPage は、取得済みのワーキンググループのページから下位の会議体を抽出するメソッドです。
+func (wg *WorkingGroup) childWorkingGroupsFromPage(doc *goquery.Document) []*WorkingGroup {
+	children := []*WorkingGroup{}
+
+	baseURL, _ := url.Parse(wg.URL)
+	seen := map[string]bool{wg.ID: true}
+
+	doc.Find(".shingi_block ul li a").Each(func(idx int, node *goquery.Selection) {
+		href, exists := node.Attr("href")
+		if !exists {
+			return
+		}
+
+		child := new(WorkingGroup)
+		child.Name = strings.TrimSpace(node.Text())
+		child.URL = toAbsURL(baseURL, href)
+		child.GetIDFromURL()
+
+		if len(child.ID) <= 0 || seen[child.ID] {
+			return
+		}
+		seen[child.ID] = true
+
+		children = append(children, child)
 	})
 
-	return workingGroups
+	return children
 }
 
 // toAbsURL はベースURLと相対URLから絶対URLを返す関数です
@@ -243,10 +530,11 @@ func toAbsURL(baseurl *url.URL, weburl string) string {
 	return absurl.String()
 }
 
-// WorkingGroupList はWorkingGroupのスライス
+// WorkingGroupList はWorkingGroupのIDをキーとするマップ
 type WorkingGroupList map[string]WorkingGroup
 
 //ImportWorkingGroupList は downloaderが出力した working_groups.json を読み込むための関数です。
+//表示順（noNN）をキーとする旧形式のファイルは、IDをキーとする形式に変換して読み込みます。
 func ImportWorkingGroupList(importFilePath string) WorkingGroupList {
 	raw, err := ioutil.ReadFile(importFilePath)
 	if err != nil {
@@ -257,5 +545,15 @@ func ImportWorkingGroupList(importFilePath string) WorkingGroupList {
 	var wgList WorkingGroupList
 	json.Unmarshal(raw, &wgList)
 
-	return wgList
+	return wgList.rekeyByID()
+}
+
+// Save は、WorkingGroupList をIDをキーとするJSONファイルとして保存するメソッドです。
+func (wgList WorkingGroupList) Save(filePath string) error {
+	data, err := json.MarshalIndent(wgList, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, data, 0666)
 }
@@ -0,0 +1,81 @@
+// Package morph は、発言の日本語形態素解析を行うためのパッケージです。
+// 辞書（IPA辞書）を同梱した pure Go の形態素解析器 kagome を使用するため、オフラインで動作します。
+package morph
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ikawaha/kagome-dict/ipa"
+	"github.com/ikawaha/kagome/v2/tokenizer"
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// Tokenizer は、形態素解析器を保持する構造体です。辞書の読み込みに時間がかかるため、作成したものを使い回してください。
+type Tokenizer struct {
+	tokenizer *tokenizer.Tokenizer
+}
+
+// NewTokenizer は、IPA辞書を読み込んで Tokenizer を作成する関数です。
+func NewTokenizer() (*Tokenizer, error) {
+	t, err := tokenizer.New(ipa.Dict(), tokenizer.OmitBosEos())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokenizer{tokenizer: t}, nil
+}
+
+// Tokenize は、文字列を形態素解析して Token の配列を返すメソッドです。空白だけの語は含めません。
+func (t *Tokenizer) Tokenize(text string) []model.Token {
+	tokens := []model.Token{}
+
+	for _, token := range t.tokenizer.Tokenize(text) {
+		if token.Class == tokenizer.DUMMY || len(strings.TrimFunc(token.Surface, unicode.IsSpace)) <= 0 {
+			continue
+		}
+
+		pos := []string{}
+		for _, p := range token.POS() {
+			if p != "*" && len(p) > 0 {
+				pos = append(pos, p)
+			}
+		}
+
+		baseForm, ok := token.BaseForm()
+		if !ok || baseForm == "*" {
+			baseForm = token.Surface
+		}
+		reading, ok := token.Reading()
+		if !ok || reading == "*" {
+			reading = ""
+		}
+
+		tokens = append(tokens, model.Token{
+			Surface:  token.Surface,
+			BaseForm: baseForm,
+			POS:      strings.Join(pos, "-"),
+			Reading:  reading,
+		})
+	}
+
+	return tokens
+}
+
+// TokenizeMinutes は、議事録に含まれる話者のある発言をすべて形態素解析し、各発言の Tokens に設定するメソッドです。
+// 段落（Talks）ごとに解析するため、段落をまたいだ語はできません。
+// ImportMinutesArrayFromHTML に MinutesHandler として渡すことで、パースしながら解析できます。
+func (t *Tokenizer) TokenizeMinutes(minutes *model.Minutes) error {
+	for _, speach := range minutes.Speaches {
+		if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+			continue
+		}
+
+		speach.Tokens = []model.Token{}
+		for _, talk := range speach.Talks {
+			speach.Tokens = append(speach.Tokens, t.Tokenize(talk)...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+// Package search は、発言の全文検索インデックスを作成・検索するためのパッケージです。
+// インデックスには Bleve を使用し、本文は CJK の bigram（2文字ずつ）に分割して索引付けします。
+package search
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// IndexDirName は、parse コマンドの出力ディレクトリに作成する検索インデックスのディレクトリ名です。
+const IndexDirName = "index.bleve"
+
+// DefaultSize は、1回の検索で返す既定の件数です。
+const DefaultSize = 20
+
+// Facets は、検索結果を集計できる項目（ファセット名とインデックスのフィールド名）です。
+var Facets = map[string]string{
+	"wg":      "wg_id",
+	"speaker": "speaker",
+	"role":    "role_class",
+	"year":    "year",
+}
+
+// Document は、検索インデックスに登録する発言1件分の文書です。
+type Document struct {
+	MeetingID      string
+	WorkingGroupID string
+	Title          string
+	Date           string
+	Year           string
+	Turn           int
+	SpeakerLabel   string
+	PersonID       string
+	// Speaker は、名寄せ済みの話者は氏名、それ以外は話者ラベルです。ファセットに使います。
+	Speaker   string
+	RoleClass string
+	Text      string
+}
+
+// fields は、Document をインデックスのフィールド名をキーとするマップに変換するメソッドです。
+func (document Document) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"meeting_id":    document.MeetingID,
+		"wg_id":         document.WorkingGroupID,
+		"title":         document.Title,
+		"date":          document.Date,
+		"year":          document.Year,
+		"turn":          document.Turn,
+		"speaker_label": document.SpeakerLabel,
+		"person_id":     document.PersonID,
+		"speaker":       document.Speaker,
+		"role_class":    document.RoleClass,
+		"text":          document.Text,
+	}
+}
+
+// documentID は、発言の文書IDを返す関数です。
+func documentID(meetingID string, turn int) string {
+	return fmt.Sprintf("%s-%d", meetingID, turn)
+}
+
+// Documents は、議事録の発言を検索インデックスに登録する文書に変換する関数です。
+// 話者が特定されていない発言と、本文が空の発言は含みません。
+func Documents(minutes *model.Minutes) []Document {
+	documents := []Document{}
+
+	speakers := map[int]*model.Speaker{}
+	for _, speach := range minutes.Speaches {
+		if speach != nil {
+			speakers[speach.Turn] = speach.Speaker
+		}
+	}
+
+	for _, record := range minutes.SpeechRecords() {
+		document := Document{
+			MeetingID:      record.MeetingID,
+			WorkingGroupID: record.WorkingGroupID,
+			Title:          minutes.Title,
+			Date:           record.Date,
+			Turn:           record.Turn,
+			SpeakerLabel:   record.SpeakerLabel,
+			PersonID:       record.PersonID,
+			Speaker:        record.SpeakerLabel,
+			RoleClass:      record.RoleClass,
+			Text:           record.Text,
+		}
+		if len(record.Date) >= 4 {
+			document.Year = record.Date[:4]
+		}
+		if speaker := speakers[record.Turn]; speaker != nil && len(speaker.Person.Name) > 0 {
+			document.Speaker = speaker.Person.Name
+		}
+		documents = append(documents, document)
+	}
+
+	return documents
+}
+
+// indexMapping は、検索インデックスのマッピングを作成する関数です。
+// 本文とタイトルは CJK の bigram で、それ以外の項目は分割せずにそのまま索引付けします。
+func indexMapping() mapping.IndexMapping {
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = cjk.AnalyzerName
+	textField.Store = true
+	textField.IncludeTermVectors = true
+
+	keywordField := bleve.NewKeywordFieldMapping()
+	keywordField.Analyzer = keyword.Name
+
+	turnField := bleve.NewNumericFieldMapping()
+
+	document := bleve.NewDocumentMapping()
+	document.AddFieldMappingsAt("text", textField)
+	document.AddFieldMappingsAt("title", textField)
+	document.AddFieldMappingsAt("turn", turnField)
+	for _, name := range []string{"meeting_id", "wg_id", "date", "year", "speaker_label", "person_id", "speaker", "role_class"} {
+		document.AddFieldMappingsAt(name, keywordField)
+	}
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = document
+	indexMapping.DefaultAnalyzer = cjk.AnalyzerName
+
+	return indexMapping
+}
+
+// Indexer は、議事録の発言を検索インデックスに逐次登録する構造体です。
+type Indexer struct {
+	index bleve.Index
+	count int
+}
+
+// NewIndexer は、path に検索インデックスを新しく作成し、Indexer を返す関数です。path に既存のインデックスがある場合は削除します。
+func NewIndexer(path string) (*Indexer, error) {
+	if err := os.RemoveAll(path); err != nil {
+		return nil, err
+	}
+
+	index, err := bleve.New(path, indexMapping())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Indexer{index: index}, nil
+}
+
+// IndexMinutes は、議事録の発言を検索インデックスに登録するメソッドです。
+// ImportMinutesArrayFromHTML に MinutesHandler として、名寄せの後に渡してください。
+func (indexer *Indexer) IndexMinutes(minutes *model.Minutes) error {
+	batch := indexer.index.NewBatch()
+	for _, document := range Documents(minutes) {
+		if err := batch.Index(documentID(document.MeetingID, document.Turn), document.fields()); err != nil {
+			return err
+		}
+	}
+	indexer.count += batch.Size()
+
+	return indexer.index.Batch(batch)
+}
+
+// Count は、これまでに登録した発言の件数を返すメソッドです。
+func (indexer *Indexer) Count() int {
+	return indexer.count
+}
+
+// Close は、検索インデックスを閉じるメソッドです。
+func (indexer *Indexer) Close() error {
+	return indexer.index.Close()
+}
+
+// BuildIndex は、MinutesArray のすべての発言を登録した検索インデックスを path に作成する関数です。
+// 同じ会議IDの議事録が複数ある場合は、最初のものだけを登録します。
+func BuildIndex(minutesArray model.MinutesArray, path string) (int, error) {
+	indexer, err := NewIndexer(path)
+	if err != nil {
+		return 0, err
+	}
+
+	meetingIDs := map[string]bool{}
+	for i := range minutesArray {
+		if meetingIDs[minutesArray[i].ID] {
+			continue
+		}
+		meetingIDs[minutesArray[i].ID] = true
+
+		if err := indexer.IndexMinutes(&minutesArray[i]); err != nil {
+			indexer.Close()
+			return 0, err
+		}
+	}
+
+	return indexer.Count(), indexer.Close()
+}
+
+// Request は、検索の条件を表す構造体です。空の項目は条件に使いません。
+type Request struct {
+	// Query は、検索語です。空白で区切った語はすべてを含む発言を、それぞれ語順どおりに探します。
+	Query string
+	// WorkingGroupIDs は、対象とするワーキンググループIDの一覧です。
+	WorkingGroupIDs []string
+	// Speaker は、話者ラベル・人物ID・氏名のいずれかと一致する発言だけを対象にします。
+	Speaker   string
+	RoleClass string
+	Year      string
+	Size      int
+	From      int
+	// Facets は、集計するファセットの名前（wg, speaker, role, year）の一覧です。
+	Facets []string
+}
+
+// Hit は、検索で見つかった発言1件を表す構造体です。
+type Hit struct {
+	Document
+	Score float64
+	// Fragments は、本文のうち検索語を含む部分です。検索語は <mark> と </mark> で囲みます。
+	Fragments []string `json:",omitempty"`
+}
+
+// FacetCount は、ファセットの値ごとの件数を表す構造体です。
+type FacetCount struct {
+	Term  string
+	Count int
+}
+
+// Result は、検索の結果を表す構造体です。
+type Result struct {
+	Total  uint64
+	Hits   []Hit
+	Facets map[string][]FacetCount `json:",omitempty"`
+}
+
+// Index は、作成済みの検索インデックスを表す構造体です。
+type Index struct {
+	index bleve.Index
+}
+
+// Open は、path の検索インデックスを開く関数です。
+func Open(path string) (*Index, error) {
+	index, err := bleve.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Index{index: index}, nil
+}
+
+// Close は、検索インデックスを閉じるメソッドです。
+func (index *Index) Close() error {
+	return index.index.Close()
+}
+
+// Search は、検索インデックスから条件に一致する発言を、関連度の高い順に返すメソッドです。
+func (index *Index) Search(req Request) (*Result, error) {
+	conjuncts := []query.Query{}
+
+	for _, term := range strings.Fields(req.Query) {
+		phrase := bleve.NewMatchPhraseQuery(term)
+		phrase.SetField("text")
+		conjuncts = append(conjuncts, phrase)
+	}
+	if len(req.WorkingGroupIDs) > 0 {
+		disjuncts := []query.Query{}
+		for _, id := range req.WorkingGroupIDs {
+			disjuncts = append(disjuncts, termQuery("wg_id", id))
+		}
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(disjuncts...))
+	}
+	if len(req.Speaker) > 0 {
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(
+			termQuery("speaker_label", req.Speaker),
+			termQuery("person_id", req.Speaker),
+			termQuery("speaker", req.Speaker),
+		))
+	}
+	if len(req.RoleClass) > 0 {
+		conjuncts = append(conjuncts, termQuery("role_class", req.RoleClass))
+	}
+	if len(req.Year) > 0 {
+		conjuncts = append(conjuncts, termQuery("year", req.Year))
+	}
+
+	var q query.Query = bleve.NewMatchAllQuery()
+	if len(conjuncts) > 0 {
+		q = bleve.NewConjunctionQuery(conjuncts...)
+	}
+
+	size := req.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	searchRequest := bleve.NewSearchRequestOptions(q, size, req.From, false)
+	searchRequest.Fields = []string{"*"}
+	if len(strings.Fields(req.Query)) > 0 {
+		searchRequest.Highlight = bleve.NewHighlight()
+		searchRequest.Highlight.AddField("text")
+	} else {
+		searchRequest.SortBy([]string{"date", "meeting_id", "turn"})
+	}
+	for _, name := range req.Facets {
+		field, exists := Facets[name]
+		if !exists {
+			return nil, fmt.Errorf("ファセット %v には対応していません。", name)
+		}
+		searchRequest.AddFacet(name, bleve.NewFacetRequest(field, 20))
+	}
+
+	searchResult, err := index.index.Search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: searchResult.Total, Hits: []Hit{}}
+	for _, match := range searchResult.Hits {
+		hit := Hit{Document: documentFromFields(match.Fields), Score: match.Score}
+		for _, fragment := range match.Fragments["text"] {
+			// bigram ごとに囲まれた検索語をひとつにまとめる
+			hit.Fragments = append(hit.Fragments, strings.ReplaceAll(fragment, "</mark><mark>", ""))
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if len(searchResult.Facets) > 0 {
+		result.Facets = map[string][]FacetCount{}
+		for name, facet := range searchResult.Facets {
+			counts := []FacetCount{}
+			if facet.Terms != nil {
+				for _, term := range facet.Terms.Terms() {
+					counts = append(counts, FacetCount{Term: term.Term, Count: term.Count})
+				}
+			}
+			result.Facets[name] = counts
+		}
+	}
+
+	return result, nil
+}
+
+// termQuery は、field の値が term と完全に一致する文書を探すクエリを作成する関数です。
+func termQuery(field string, term string) query.Query {
+	q := bleve.NewTermQuery(term)
+	q.SetField(field)
+	return q
+}
+
+// documentFromFields は、検索結果に含まれる保存済みの項目から Document を作成する関数です。
+func documentFromFields(fields map[string]interface{}) Document {
+	str := func(name string) string {
+		s, _ := fields[name].(string)
+		return s
+	}
+
+	document := Document{
+		MeetingID:      str("meeting_id"),
+		WorkingGroupID: str("wg_id"),
+		Title:          str("title"),
+		Date:           str("date"),
+		Year:           str("year"),
+		SpeakerLabel:   str("speaker_label"),
+		PersonID:       str("person_id"),
+		Speaker:        str("speaker"),
+		RoleClass:      str("role_class"),
+		Text:           str("text"),
+	}
+	if turn, ok := fields["turn"].(float64); ok {
+		document.Turn = int(turn)
+	}
+
+	return document
+}
+
+// PlainFragment は、検索結果の断片から HTML のタグを除き、検索語を【】で囲んだ文字列を返す関数です。
+func PlainFragment(fragment string) string {
+	fragment = strings.NewReplacer("<mark>", "【", "</mark>", "】").Replace(fragment)
+	return html.UnescapeString(fragment)
+}
@@ -0,0 +1,369 @@
+// Package server は、パース済みの議事録を読み取り専用の REST/JSON API として提供するためのパッケージです。
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/search"
+)
+
+// DefaultLimit と MaxLimit は、一覧を返す API の1ページあたりの既定と上限の件数です。
+const (
+	DefaultLimit = 50
+	MaxLimit     = 1000
+)
+
+// openAPI は、API の OpenAPI 記述です。
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// Server は、Corpus と検索インデックスを API として提供する構造体です。http.Handler として使います。
+type Server struct {
+	corpus *model.Corpus
+	index  *search.Index
+	mux    *http.ServeMux
+
+	minutes map[string]*model.Minutes
+	persons map[string]model.Person
+}
+
+// MeetingSummary は、会議の一覧で返す会議1件分の情報です。
+type MeetingSummary struct {
+	ID             string
+	WorkingGroupID string
+	MeetingNumber  int
+	Title          string
+	Date           string
+	Venue          string
+	SpeechCount    int
+}
+
+// PersonDetail は、人物1人分の詳細な情報です。
+type PersonDetail struct {
+	model.Person
+	Memberships []model.Membership
+	MeetingIDs  []string
+}
+
+// Page は、一覧を返す API の1ページ分の結果です。
+type Page struct {
+	Total  int
+	Offset int
+	Limit  int
+	Items  interface{}
+}
+
+// SearchPage は、検索の API の1ページ分の結果です。Items は search.Hit の配列です。
+type SearchPage struct {
+	Page
+	Facets map[string][]search.FacetCount `json:",omitempty"`
+}
+
+// errorResponse は、エラーの内容を返す際の JSON です。
+type errorResponse struct {
+	Error string
+}
+
+// New は、Corpus を提供する Server を作成する関数です。index が nil の場合、検索の API はエラーを返します。
+func New(corpus *model.Corpus, index *search.Index) *Server {
+	server := &Server{
+		corpus:  corpus,
+		index:   index,
+		mux:     http.NewServeMux(),
+		minutes: map[string]*model.Minutes{},
+		persons: map[string]model.Person{},
+	}
+
+	for i := range corpus.Minutes {
+		if _, exists := server.minutes[corpus.Minutes[i].ID]; !exists {
+			server.minutes[corpus.Minutes[i].ID] = &corpus.Minutes[i]
+		}
+	}
+	for _, person := range corpus.Persons() {
+		server.persons[person.ID] = person
+	}
+
+	server.mux.HandleFunc("GET /openapi.json", server.handleOpenAPI)
+	server.mux.HandleFunc("GET /api/working-groups", server.handleWorkingGroups)
+	server.mux.HandleFunc("GET /api/working-groups/{id}", server.handleWorkingGroup)
+	server.mux.HandleFunc("GET /api/meetings", server.handleMeetings)
+	server.mux.HandleFunc("GET /api/meetings/{id}", server.handleMeeting)
+	server.mux.HandleFunc("GET /api/persons", server.handlePersons)
+	server.mux.HandleFunc("GET /api/persons/{id}", server.handlePerson)
+	server.mux.HandleFunc("GET /api/speeches", server.handleSpeeches)
+	server.mux.HandleFunc("GET /api/search", server.handleSearch)
+
+	return server
+}
+
+// ServeHTTP は、リクエストを各 API に振り分けるメソッドです。
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.ServeHTTP(w, r)
+}
+
+// writeJSON は、値を JSON として書き出す関数です。
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("WARN: レスポンスを書き出せませんでした。%v\n", err)
+	}
+}
+
+// writeError は、エラーの内容を JSON として書き出す関数です。
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// filterFromQuery は、クエリパラメータ（wg, children, speaker, role, from, to）から analysis.Filter を作成するメソッドです。
+func (server *Server) filterFromQuery(r *http.Request) analysis.Filter {
+	q := r.URL.Query()
+	withChildren, _ := strconv.ParseBool(q.Get("children"))
+
+	return analysis.NewFilter(server.corpus.WorkingGroups, q.Get("wg"), withChildren, q.Get("speaker"), q.Get("role"), q.Get("from"), q.Get("to"))
+}
+
+// pageFromQuery は、クエリパラメータ（offset, limit）からページの位置と件数を返す関数です。
+func pageFromQuery(r *http.Request) (offset int, limit int, ok bool) {
+	q := r.URL.Query()
+	offset, limit = 0, DefaultLimit
+
+	var err error
+	if s := q.Get("offset"); len(s) > 0 {
+		if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+			return 0, 0, false
+		}
+	}
+	if s := q.Get("limit"); len(s) > 0 {
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			return 0, 0, false
+		}
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	return offset, limit, true
+}
+
+// paginate は、配列の offset 番目から limit 件を返す関数です。
+func paginate[T any](items []T, offset int, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[offset:end]
+}
+
+// handleOpenAPI は、API の OpenAPI 記述を返すメソッドです。
+func (server *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(openAPI)
+}
+
+// handleWorkingGroups は、ワーキンググループの一覧をID順に返すメソッドです。
+func (server *Server) handleWorkingGroups(w http.ResponseWriter, r *http.Request) {
+	ids := []string{}
+	for id := range server.corpus.WorkingGroups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	wgs := []model.WorkingGroup{}
+	for _, id := range ids {
+		wgs = append(wgs, server.corpus.WorkingGroups[id])
+	}
+	writeJSON(w, http.StatusOK, wgs)
+}
+
+// handleWorkingGroup は、ワーキンググループ1件を返すメソッドです。
+func (server *Server) handleWorkingGroup(w http.ResponseWriter, r *http.Request) {
+	wg, exists := server.corpus.WorkingGroups[r.PathValue("id")]
+	if !exists {
+		writeError(w, http.StatusNotFound, "ワーキンググループ "+r.PathValue("id")+" は見つかりません。")
+		return
+	}
+	writeJSON(w, http.StatusOK, wg)
+}
+
+// handleMeetings は、条件に一致する会議の一覧を開催日順に返すメソッドです。
+func (server *Server) handleMeetings(w http.ResponseWriter, r *http.Request) {
+	offset, limit, ok := pageFromQuery(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "offset と limit には0以上の整数を指定してください。")
+		return
+	}
+
+	filter := server.filterFromQuery(r)
+	meetings := []MeetingSummary{}
+	seen := map[string]bool{}
+	for i := range server.corpus.Minutes {
+		minutes := &server.corpus.Minutes[i]
+		if seen[minutes.ID] || !filter.MatchMinutes(minutes) {
+			continue
+		}
+		seen[minutes.ID] = true
+
+		meetings = append(meetings, MeetingSummary{
+			ID:             minutes.ID,
+			WorkingGroupID: minutes.WorkingGroupID,
+			MeetingNumber:  minutes.MeetingNumber,
+			Title:          minutes.Title,
+			Date:           minutes.Date,
+			Venue:          minutes.Venue,
+			SpeechCount:    len(minutes.SpeechRecords()),
+		})
+	}
+	sort.SliceStable(meetings, func(i, j int) bool {
+		if meetings[i].Date != meetings[j].Date {
+			return meetings[i].Date < meetings[j].Date
+		}
+		return meetings[i].ID < meetings[j].ID
+	})
+
+	writeJSON(w, http.StatusOK, Page{Total: len(meetings), Offset: offset, Limit: limit, Items: paginate(meetings, offset, limit)})
+}
+
+// handleMeeting は、会議1件の議事録を、発言を含めて返すメソッドです。
+func (server *Server) handleMeeting(w http.ResponseWriter, r *http.Request) {
+	minutes, exists := server.minutes[r.PathValue("id")]
+	if !exists {
+		writeError(w, http.StatusNotFound, "会議 "+r.PathValue("id")+" は見つかりません。")
+		return
+	}
+	writeJSON(w, http.StatusOK, minutes)
+}
+
+// handlePersons は、人物の一覧をID順に返すメソッドです。name を指定した場合は、氏名に name を含む人物だけを返します。
+func (server *Server) handlePersons(w http.ResponseWriter, r *http.Request) {
+	offset, limit, ok := pageFromQuery(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "offset と limit には0以上の整数を指定してください。")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	persons := []model.Person{}
+	for _, person := range server.corpus.Persons() {
+		if len(name) > 0 && !strings.Contains(person.Name, name) {
+			continue
+		}
+		persons = append(persons, person)
+	}
+
+	writeJSON(w, http.StatusOK, Page{Total: len(persons), Offset: offset, Limit: limit, Items: paginate(persons, offset, limit)})
+}
+
+// handlePerson は、人物1人の情報を、所属と発言した会議とあわせて返すメソッドです。
+func (server *Server) handlePerson(w http.ResponseWriter, r *http.Request) {
+	person, exists := server.persons[r.PathValue("id")]
+	if !exists {
+		writeError(w, http.StatusNotFound, "人物 "+r.PathValue("id")+" は見つかりません。")
+		return
+	}
+
+	detail := PersonDetail{Person: person, Memberships: []model.Membership{}, MeetingIDs: []string{}}
+	for _, membership := range server.corpus.Memberships() {
+		if membership.PersonID == person.ID {
+			detail.Memberships = append(detail.Memberships, membership)
+		}
+	}
+	for _, minutes := range server.minutes {
+		for _, speaker := range minutes.Speakers {
+			if speaker != nil && speaker.Person.ID == person.ID {
+				detail.MeetingIDs = append(detail.MeetingIDs, minutes.ID)
+				break
+			}
+		}
+	}
+	sort.Strings(detail.MeetingIDs)
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// handleSpeeches は、条件に一致する発言の一覧を会議・発言番号の順に返すメソッドです。q を指定した場合は、本文に q を含む発言だけを返します。
+func (server *Server) handleSpeeches(w http.ResponseWriter, r *http.Request) {
+	offset, limit, ok := pageFromQuery(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "offset と limit には0以上の整数を指定してください。")
+		return
+	}
+
+	text := r.URL.Query().Get("q")
+	meetingID := r.URL.Query().Get("meeting")
+	if _, exists := server.minutes[meetingID]; len(meetingID) > 0 && !exists {
+		writeError(w, http.StatusNotFound, "会議 "+meetingID+" は見つかりません。")
+		return
+	}
+
+	records := []model.SpeechRecord{}
+	for _, speech := range analysis.Speeches(server.corpus, server.filterFromQuery(r)) {
+		if len(meetingID) > 0 && speech.MeetingID != meetingID {
+			continue
+		}
+		if len(text) > 0 && !strings.Contains(speech.Text, text) {
+			continue
+		}
+		records = append(records, speech.SpeechRecord)
+	}
+
+	writeJSON(w, http.StatusOK, Page{Total: len(records), Offset: offset, Limit: limit, Items: paginate(records, offset, limit)})
+}
+
+// handleSearch は、検索インデックスから発言を検索した結果を返すメソッドです。
+func (server *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if server.index == nil {
+		writeError(w, http.StatusServiceUnavailable, "検索インデックスがありません。index コマンドで作成してください。")
+		return
+	}
+
+	offset, limit, ok := pageFromQuery(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "offset と limit には0以上の整数を指定してください。")
+		return
+	}
+
+	q := r.URL.Query()
+	req := search.Request{Query: q.Get("q"), Speaker: q.Get("speaker"), RoleClass: q.Get("role"), Year: q.Get("year"), Size: limit, From: offset}
+	for _, id := range strings.Split(q.Get("wg"), ",") {
+		if id = strings.TrimSpace(id); len(id) > 0 {
+			req.WorkingGroupIDs = append(req.WorkingGroupIDs, id)
+		}
+	}
+	for _, name := range strings.Split(q.Get("facets"), ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			if _, exists := search.Facets[name]; !exists {
+				writeError(w, http.StatusBadRequest, "ファセット "+name+" には対応していません。")
+				return
+			}
+			req.Facets = append(req.Facets, name)
+		}
+	}
+
+	result, err := server.index.Search(req)
+	if err != nil {
+		log.Printf("WARN: 検索に失敗しました。%v\n", err)
+		writeError(w, http.StatusInternalServerError, "検索に失敗しました。")
+		return
+	}
+	writeJSON(w, http.StatusOK, SearchPage{
+		Page:   Page{Total: int(result.Total), Offset: offset, Limit: limit, Items: result.Hits},
+		Facets: result.Facets,
+	})
+}
@@ -0,0 +1,418 @@
+// Package site は、パース済みの議事録から、ブラウザだけで閲覧できる静的サイトを作成するためのパッケージです。
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"hash/fnv"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// assets は、サイトのテンプレートと静的ファイルです。
+//
+//go:embed templates static
+var assets embed.FS
+
+// SiteName は、サイトの見出しに表示する名前です。
+const SiteName = "中央教育審議会 議事録"
+
+// page は、テンプレートに渡すページ共通のデータです。
+type page struct {
+	Title    string
+	Root     string
+	SiteName string
+	Version  string
+	Data     interface{}
+}
+
+// workingGroupItem は、ワーキンググループの一覧とページに表示する内容です。
+type workingGroupItem struct {
+	model.WorkingGroup
+	Depth    int
+	Parent   *model.WorkingGroup
+	Children []model.WorkingGroup
+	Meetings []meetingItem
+	Members  []memberItem
+}
+
+// meetingItem は、会議の一覧とページに表示する内容です。
+type meetingItem struct {
+	ID            string
+	Title         string
+	Date          string
+	Venue         string
+	MeetingNumber int
+	SpeechCount   int
+	WorkingGroup  model.WorkingGroup
+	SourceURL     string
+	Materials     []model.Material
+	Speakers      []speakerItem
+	Speeches      []speechItem
+}
+
+// memberItem は、ワーキンググループの名簿に表示する委員です。
+type memberItem struct {
+	Person model.Person
+	Role   string
+}
+
+// speakerItem は、会議の話者の凡例に表示する内容です。
+type speakerItem struct {
+	Label    string
+	PersonID string
+	Hue      int
+}
+
+// speechItem は、会議ページや人物ページに表示する発言です。
+type speechItem struct {
+	Turn     int
+	Label    string
+	PersonID string
+	Hue      int
+	Talks    []string
+}
+
+// personItem は、人物ページに表示する内容です。
+type personItem struct {
+	model.Person
+	Hue         int
+	Memberships []membershipItem
+	Meetings    []personMeetingItem
+	SpeechCount int
+}
+
+// membershipItem は、人物が所属するワーキンググループです。
+type membershipItem struct {
+	WorkingGroup model.WorkingGroup
+	Role         string
+}
+
+// personMeetingItem は、人物ページに会議ごとにまとめて表示する発言です。
+type personMeetingItem struct {
+	Meeting  meetingItem
+	Speeches []speechItem
+}
+
+// searchEntry は、クライアント側の検索に使用する発言1件分のデータです。ファイルを小さくするため、キーを短くしています。
+type searchEntry struct {
+	MeetingID    string `json:"m"`
+	Title        string `json:"t"`
+	Date         string `json:"d"`
+	WorkingGroup string `json:"w"`
+	Turn         int    `json:"n"`
+	Speaker      string `json:"s"`
+	PersonID     string `json:"p,omitempty"`
+	Text         string `json:"x"`
+}
+
+// hue は、話者を色分けするための色相を、人物IDまたは話者ラベルから求める関数です。同じ話者には常に同じ色が付きます。
+func hue(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % 360)
+}
+
+// speakerKey は、話者の色分けに使用するキーを返す関数です。
+func speakerKey(speaker *model.Speaker) string {
+	if len(speaker.Person.ID) > 0 {
+		return speaker.Person.ID
+	}
+	return speaker.Label
+}
+
+// Generate は、Corpus から静的サイトを outputdir に作成する関数です。
+// ワーキンググループの一覧（index.html）、ワーキンググループ・会議・人物ごとのページと、検索ページを作成します。
+// 検索用の索引はスクリプトとして書き出すため、サーバーを用意せずにファイルを直接開いて閲覧できます。
+func Generate(corpus *model.Corpus, outputdir string) error {
+	for _, dir := range []string{"wg", "meeting", "person"} {
+		if err := os.MkdirAll(filepath.Join(outputdir, dir), 0777); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{"style.css", "search.js"} {
+		content, err := assets.ReadFile("static/" + name)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(outputdir, name), content, 0666); err != nil {
+			return err
+		}
+	}
+
+	g := newGenerator(corpus)
+	workingGroups := g.workingGroupItems()
+
+	if err := g.render("index.html", filepath.Join(outputdir, "index.html"), page{Title: SiteName, Root: "", Data: workingGroups}); err != nil {
+		return err
+	}
+	if err := g.render("search.html", filepath.Join(outputdir, "search.html"), page{Title: "検索", Root: "", Data: workingGroups}); err != nil {
+		return err
+	}
+	if err := g.writeSearchIndex(filepath.Join(outputdir, "search-index.js")); err != nil {
+		return err
+	}
+
+	for _, item := range workingGroups {
+		if err := g.render("wg.html", filepath.Join(outputdir, "wg", item.ID+".html"), page{Title: item.Name, Root: "../", Data: item}); err != nil {
+			return err
+		}
+	}
+
+	for _, meeting := range g.meetings {
+		if err := g.render("meeting.html", filepath.Join(outputdir, "meeting", meeting.ID+".html"), page{Title: meeting.Title, Root: "../", Data: meeting}); err != nil {
+			return err
+		}
+	}
+
+	for _, person := range g.personItems() {
+		if err := g.render("person.html", filepath.Join(outputdir, "person", person.ID+".html"), page{Title: person.Name, Root: "../", Data: person}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generator は、サイトの作成中に Corpus から組み立てたデータを保持する構造体です。
+type generator struct {
+	corpus        *model.Corpus
+	workingGroups model.WorkingGroupList
+	meetings      []meetingItem
+	templates     map[string]*template.Template
+}
+
+// newGenerator は、Corpus から generator を作成する関数です。議事録や名簿から参照されているが一覧にないワーキンググループも補います。
+func newGenerator(corpus *model.Corpus) *generator {
+	g := &generator{corpus: corpus, workingGroups: model.WorkingGroupList{}, templates: map[string]*template.Template{}}
+
+	for id, wg := range corpus.WorkingGroups {
+		g.workingGroups[id] = wg
+	}
+	for _, minutes := range corpus.Minutes {
+		if _, exists := g.workingGroups[minutes.WorkingGroupID]; !exists {
+			g.workingGroups[minutes.WorkingGroupID] = model.WorkingGroup{ID: minutes.WorkingGroupID, Name: minutes.WorkingGroup}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, minutes := range corpus.Minutes {
+		if seen[minutes.ID] {
+			continue
+		}
+		seen[minutes.ID] = true
+		g.meetings = append(g.meetings, g.meetingItem(minutes))
+	}
+	sort.SliceStable(g.meetings, func(i, j int) bool {
+		if g.meetings[i].Date != g.meetings[j].Date {
+			return g.meetings[i].Date < g.meetings[j].Date
+		}
+		return g.meetings[i].ID < g.meetings[j].ID
+	})
+
+	return g
+}
+
+// meetingItem は、議事録を会議ページに表示する内容に変換するメソッドです。
+func (g *generator) meetingItem(minutes model.Minutes) meetingItem {
+	item := meetingItem{
+		ID:            minutes.ID,
+		Title:         minutes.Title,
+		Date:          minutes.Date,
+		Venue:         minutes.Venue,
+		MeetingNumber: minutes.MeetingNumber,
+		WorkingGroup:  g.workingGroups[minutes.WorkingGroupID],
+		SourceURL:     minutes.Provenance.SourceURL,
+		Materials:     minutes.Materials,
+	}
+
+	speakers := map[string]bool{}
+	for _, speach := range minutes.Speaches {
+		if speach == nil || speach.Speaker == nil || len(speach.Talks) <= 0 {
+			continue
+		}
+
+		speech := speechItem{
+			Turn:     speach.Turn,
+			Label:    speach.Speaker.Label,
+			PersonID: speach.Speaker.Person.ID,
+			Hue:      hue(speakerKey(speach.Speaker)),
+			Talks:    speach.Talks,
+		}
+		item.Speeches = append(item.Speeches, speech)
+
+		if !speakers[speech.Label] {
+			speakers[speech.Label] = true
+			item.Speakers = append(item.Speakers, speakerItem{Label: speech.Label, PersonID: speech.PersonID, Hue: speech.Hue})
+		}
+	}
+	item.SpeechCount = len(item.Speeches)
+
+	return item
+}
+
+// workingGroupItems は、ワーキンググループの一覧を階層順（親の直後に子）に並べて返すメソッドです。
+func (g *generator) workingGroupItems() []workingGroupItem {
+	members := map[string][]memberItem{}
+	persons := map[string]model.Person{}
+	for _, person := range g.corpus.Persons() {
+		persons[person.ID] = person
+	}
+	for _, membership := range g.corpus.Memberships() {
+		members[membership.WorkingGroupID] = append(members[membership.WorkingGroupID], memberItem{Person: persons[membership.PersonID], Role: membership.Role})
+	}
+
+	meetings := map[string][]meetingItem{}
+	for _, meeting := range g.meetings {
+		meetings[meeting.WorkingGroup.ID] = append(meetings[meeting.WorkingGroup.ID], meeting)
+	}
+
+	roots := []string{}
+	for id, wg := range g.workingGroups {
+		if _, exists := g.workingGroups[wg.ParentID]; !exists || len(wg.ParentID) <= 0 {
+			roots = append(roots, id)
+		}
+	}
+	sort.Strings(roots)
+
+	items := []workingGroupItem{}
+	visited := map[string]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		wg := g.workingGroups[id]
+		item := workingGroupItem{WorkingGroup: wg, Depth: g.workingGroups.Depth(id), Meetings: meetings[id], Members: members[id]}
+		if parent, exists := g.workingGroups[wg.ParentID]; exists && len(wg.ParentID) > 0 {
+			item.Parent = &parent
+		}
+		children := g.workingGroups.Children(id)
+		for _, childID := range children {
+			item.Children = append(item.Children, g.workingGroups[childID])
+		}
+		items = append(items, item)
+
+		for _, childID := range children {
+			visit(childID)
+		}
+	}
+	for _, id := range roots {
+		visit(id)
+	}
+
+	return items
+}
+
+// personItems は、名簿と名寄せ済みの話者に現れる人物ごとに、所属と発言をまとめて返すメソッドです。
+func (g *generator) personItems() []personItem {
+	memberships := map[string][]membershipItem{}
+	for _, membership := range g.corpus.Memberships() {
+		memberships[membership.PersonID] = append(memberships[membership.PersonID], membershipItem{WorkingGroup: g.workingGroups[membership.WorkingGroupID], Role: membership.Role})
+	}
+
+	meetings := map[string][]personMeetingItem{}
+	for _, meeting := range g.meetings {
+		byPerson := map[string][]speechItem{}
+		order := []string{}
+		for _, speech := range meeting.Speeches {
+			if len(speech.PersonID) <= 0 {
+				continue
+			}
+			if _, exists := byPerson[speech.PersonID]; !exists {
+				order = append(order, speech.PersonID)
+			}
+			byPerson[speech.PersonID] = append(byPerson[speech.PersonID], speech)
+		}
+		for _, personID := range order {
+			meetings[personID] = append(meetings[personID], personMeetingItem{Meeting: meeting, Speeches: byPerson[personID]})
+		}
+	}
+
+	items := []personItem{}
+	for _, person := range g.corpus.Persons() {
+		item := personItem{Person: person, Hue: hue(person.ID), Memberships: memberships[person.ID], Meetings: meetings[person.ID]}
+		for _, meeting := range item.Meetings {
+			item.SpeechCount += len(meeting.Speeches)
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// writeSearchIndex は、検索ページで使用する索引をスクリプトとして書き出すメソッドです。
+// file:// で開いた場合でも読み込めるよう、JSON ではなく変数への代入として書き出します。
+func (g *generator) writeSearchIndex(filePath string) error {
+	entries := []searchEntry{}
+	for _, meeting := range g.meetings {
+		for _, speech := range meeting.Speeches {
+			text := ""
+			for i, talk := range speech.Talks {
+				if i > 0 {
+					text += "\n"
+				}
+				text += talk
+			}
+			entries = append(entries, searchEntry{
+				MeetingID:    meeting.ID,
+				Title:        meeting.Title,
+				Date:         meeting.Date,
+				WorkingGroup: meeting.WorkingGroup.ID,
+				Turn:         speech.Turn,
+				Speaker:      speech.Label,
+				PersonID:     speech.PersonID,
+				Text:         text,
+			})
+		}
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString("var MEROKU_SEARCH_INDEX = "); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(true)
+	if err := encoder.Encode(entries); err != nil {
+		return err
+	}
+	_, err = file.WriteString(";\n")
+	return err
+}
+
+// render は、テンプレートでページを作成して filePath に書き出すメソッドです。
+func (g *generator) render(name string, filePath string, p page) error {
+	tmpl, exists := g.templates[name]
+	if !exists {
+		var err error
+		tmpl, err = template.ParseFS(assets, "templates/layout.html", "templates/"+name)
+		if err != nil {
+			return err
+		}
+		g.templates[name] = tmpl
+	}
+
+	p.SiteName = SiteName
+	p.Version = model.MerokuVersion
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tmpl.ExecuteTemplate(file, "layout", p)
+}
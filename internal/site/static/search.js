@@ -0,0 +1,76 @@
+// 検索ページの処理です。search-index.js で定義される MEROKU_SEARCH_INDEX を部分一致で検索します。
+(function () {
+  "use strict";
+
+  var maxResults = 200;
+  var contextLength = 60;
+
+  var form = document.getElementById("search-form");
+  var queryInput = document.getElementById("search-query");
+  var wgSelect = document.getElementById("search-wg");
+  var status = document.getElementById("search-status");
+  var results = document.getElementById("search-results");
+
+  function element(name, className, text) {
+    var e = document.createElement(name);
+    if (className) {
+      e.className = className;
+    }
+    if (text) {
+      e.textContent = text;
+    }
+    return e;
+  }
+
+  function snippet(text, index, length) {
+    var p = element("p");
+    var start = Math.max(0, index - contextLength);
+    var end = Math.min(text.length, index + length + contextLength);
+    p.appendChild(document.createTextNode((start > 0 ? "…" : "") + text.slice(start, index)));
+    p.appendChild(element("mark", "", text.slice(index, index + length)));
+    p.appendChild(document.createTextNode(text.slice(index + length, end) + (end < text.length ? "…" : "")));
+    return p;
+  }
+
+  function search() {
+    var query = queryInput.value.trim();
+    var wg = wgSelect.value;
+    results.textContent = "";
+    if (query.length === 0) {
+      status.textContent = "";
+      return;
+    }
+
+    var lowerQuery = query.toLowerCase();
+    var count = 0;
+    MEROKU_SEARCH_INDEX.forEach(function (entry) {
+      if (wg && entry.w !== wg) {
+        return;
+      }
+      var index = entry.x.toLowerCase().indexOf(lowerQuery);
+      if (index < 0) {
+        return;
+      }
+      count++;
+      if (count > maxResults) {
+        return;
+      }
+
+      var section = element("section", "speech");
+      var heading = element("h4");
+      var link = element("a", "", entry.s + "　" + entry.t + "（" + entry.d + "）");
+      link.href = "meeting/" + entry.m + ".html#t" + entry.n;
+      heading.appendChild(link);
+      section.appendChild(heading);
+      section.appendChild(snippet(entry.x, index, query.length));
+      results.appendChild(section);
+    });
+
+    status.textContent = count + " 件の発言が見つかりました。" + (count > maxResults ? "最初の " + maxResults + " 件を表示しています。" : "");
+  }
+
+  form.addEventListener("submit", function (event) {
+    event.preventDefault();
+    search();
+  });
+})();
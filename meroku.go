@@ -1,13 +1,23 @@
 package main
 
 import (
+	_ "embed"
 	"flag"
 	"os"
+	"strings"
 
 	"github.com/tsunekawa/meroku/cmd"
+	"github.com/tsunekawa/meroku/internal/model"
 )
 
+// version は、VERSION ファイルに記載された meroku のバージョンです。
+//
+//go:embed VERSION
+var version string
+
 func main() {
+	model.MerokuVersion = strings.TrimSpace(version)
+
 	flag.Parse()
 	args := flag.Args()
 
@@ -16,6 +26,30 @@ func main() {
 		cmd.DownloadCmd(args[1:])
 	case "parse":
 		cmd.ParseCmd(args[1:])
+	case "export":
+		cmd.ExportCmd(args[1:])
+	case "import":
+		cmd.ImportCmd(args[1:])
+	case "site":
+		cmd.SiteCmd(args[1:])
+	case "tokenize":
+		cmd.TokenizeCmd(args[1:])
+	case "stats":
+		cmd.StatsCmd(args[1:])
+	case "kwic":
+		cmd.KWICCmd(args[1:])
+	case "index":
+		cmd.IndexCmd(args[1:])
+	case "search":
+		cmd.SearchCmd(args[1:])
+	case "serve":
+		cmd.ServeCmd(args[1:])
+	case "network":
+		cmd.NetworkCmd(args[1:])
+	case "terms":
+		cmd.TermsCmd(args[1:])
+	case "topics":
+		cmd.TopicsCmd(args[1:])
 	default:
 		flag.Usage()
 		os.Exit(1)
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+func ExampleMinutes_SegmentAgenda() {
+	chair := &model.Speaker{Label: "荒瀬部会長"}
+	staff := &model.Speaker{Label: "髙谷教育課程課長"}
+	member := &model.Speaker{Label: "﨑山委員"}
+
+	minutes := model.Minutes{
+		Topics: []string{"（１）新しい時代の初等中等教育の在り方について", "（２）その他"},
+		Speaches: []*model.Speach{
+			{Turn: 1, Speaker: chair, Talks: []string{"ただいまから第１３回を開催いたします。"}},
+			{Turn: 2, Speaker: chair, Talks: []string{"それでは、議題１に移ります。事務局から説明をお願いします。"}},
+			{Turn: 3, Speaker: staff, Talks: []string{"GIGAスクール構想について説明いたします。"}},
+			{Turn: 4, Speaker: member, Talks: []string{"議題３に移る前に質問があります。"}},
+			{Turn: 5, Speaker: chair, Talks: []string{"それでは、議題２に移ります。"}},
+			{Turn: 6, Speaker: chair, Talks: []string{"議題５に移ります。"}},
+			{Turn: 7, Speaker: member, Talks: []string{"特にありません。"}},
+		},
+	}
+
+	minutes.SegmentAgenda()
+	for _, speach := range minutes.Speaches {
+		fmt.Printf("%v %v %q\n", speach.Turn, speach.AgendaItem, minutes.AgendaTitle(speach.AgendaItem))
+	}
+
+	number, ok := model.AgendaNumber("（２）その他")
+	fmt.Println(number, ok)
+	next, ok := model.AgendaCue("続きまして、次の議題に移ります。", 2)
+	fmt.Println(next, ok)
+	// Output:
+	// 1 0 ""
+	// 2 1 "（１）新しい時代の初等中等教育の在り方について"
+	// 3 1 "（１）新しい時代の初等中等教育の在り方について"
+	// 4 1 "（１）新しい時代の初等中等教育の在り方について"
+	// 5 2 "（２）その他"
+	// 6 2 "（２）その他"
+	// 7 2 "（２）その他"
+	// 2 true
+	// 3 true
+}
+
+// 議事録冒頭の「議題」の一覧を読み取り、発言を議題ごとに区分する場合
+func ExampleParseMinutesFromFile_agenda() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	html := `<html><body><div id="contentsMain">
+<h1>新しい時代の初等中等教育の在り方特別部会（第１３回）　議事録</h1>
+<h2>議事録</h2>
+<p>１．日時<br/>令和２年９月２８日（月曜日）１０時００分～１２時００分</p>
+<p>２．議題<br/>（１）新しい時代の初等中等教育の在り方について<br/>（２）その他<br/>３．配付資料<br/>資料１　審議の状況</p>
+<p>４．議事録<br/>【荒瀬部会長】　それでは、ただいまから第１３回を開催いたします。<br/>議題１に移ります。<br/>【髙谷教育課程課長】　説明いたします。<br/>【荒瀬部会長】　それでは、議題２に移ります。<br/>【﨑山委員】　特にありません。</p>
+</div></body></html>`
+	fileName := filepath.Join(dir, "wg083-1422565_00013.htm")
+	if err := ioutil.WriteFile(fileName, []byte(html), 0666); err != nil {
+		log.Fatal(err)
+	}
+
+	minutes := model.ParseMinutesFromFile(fileName)
+	fmt.Println(len(minutes.Topics), minutes.Topics)
+	for _, speach := range minutes.Speaches {
+		if speach.Speaker != nil {
+			fmt.Println(speach.Speaker.Label, speach.AgendaItem)
+		}
+	}
+	// Output:
+	// 2 [（１）新しい時代の初等中等教育の在り方について （２）その他]
+	// 荒瀬部会長 1
+	// 髙谷教育課程課長 1
+	// 荒瀬部会長 2
+	// 﨑山委員 2
+}
+
+// 議題の区分が導入される前に出力された議事録を読み込む場合
+func ExampleLoadCorpus_agenda() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	legacy := `[{"ID": "wg083-013", "WorkingGroupID": "083", "Speaches": [
+		{"Turn": 1, "Speaker": {"Label": "荒瀬部会長"}, "Talks": ["開会いたします。"]},
+		{"Turn": 2, "Speaker": {"Label": "荒瀬部会長"}, "Talks": ["それでは、議題１に移ります。"]},
+		{"Turn": 3, "Speaker": {"Label": "﨑山委員"}, "Talks": ["意見を申し上げます。"]}
+	]}]`
+	if err := ioutil.WriteFile(filepath.Join(dir, "all.json"), []byte(legacy), 0666); err != nil {
+		log.Fatal(err)
+	}
+
+	corpus, err := model.LoadCorpus(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, speach := range corpus.Minutes[0].Speaches {
+		fmt.Println(speach.Turn, speach.AgendaItem)
+	}
+
+	// 議題ごとの割合は、話者の条件で絞り込んでも会議のすべての発言に対するものになる
+	filter := analysis.Filter{RoleClass: model.RoleClassMember, AgendaItems: map[int]bool{1: true}}
+	rows, err := analysis.Stats(corpus, analysis.GroupByAgenda, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Printf("%v %v %.2f\n", row.Key, row.Speeches, row.SpeechShare)
+	}
+	// Output:
+	// 1 0
+	// 2 1
+	// 3 1
+	// wg083-013/1 2 0.67
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tsunekawa/meroku/internal/analysis"
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+)
+
+func ExampleStats() {
+	corpus := exampleCorpus()
+
+	rows, err := analysis.Stats(corpus, analysis.GroupByPerson, analysis.Filter{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Printf("%v %v %v %.2f\n", row.Label, row.Speeches, row.Characters, row.CharacterShare)
+	}
+
+	filter := analysis.NewFilter(corpus.WorkingGroups, "083", false, "", model.RoleClassMember, "2020", "2020")
+	rows, err = analysis.Stats(corpus, analysis.GroupByMeeting, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(rows[0].Key, rows[0].Speeches, rows[0].MemberRatio)
+
+	// 話者の条件で絞り込んでも、割合は会議のすべての発言に対するものになる
+	rows, err = analysis.Stats(corpus, analysis.GroupByMeetingPerson, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%v %.2f %.2f\n", rows[0].Label, rows[0].SpeechShare, rows[0].CharacterShare)
+	// Output:
+	// 髙谷教育課程課長 1 22 0.55
+	// 荒瀬克己 2 18 0.45
+	// wg083-013 2 1
+	// 荒瀬克己 0.67 0.45
+}
+
+func ExampleKWIC() {
+	corpus := exampleCorpus()
+
+	pattern, err := analysis.CompileKWICPattern("議題[０-９]", true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, hit := range analysis.KWIC(corpus, pattern, analysis.Filter{}, 5) {
+		fmt.Printf("%v %v [%v] %v|%v|%v\n", hit.MeetingID, hit.Turn, hit.SpeakerLabel, hit.Left, hit.Keyword, hit.Right)
+	}
+
+	pattern, err = analysis.CompileKWICPattern("GIGA", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	filter := analysis.NewFilter(corpus.WorkingGroups, "", false, "荒瀬克己", "", "", "")
+	fmt.Println(len(analysis.KWIC(corpus, pattern, filter, 5)))
+	// Output:
+	// wg083-013 1 [荒瀬部会長] それでは、|議題１|に移ります
+	// 0
+}
+
+func ExampleNetwork() {
+	corpus := exampleCorpus()
+
+	graph, err := analysis.Network(corpus, analysis.NetworkTurn, analysis.Filter{}, false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(graph.Nodes), len(graph.Edges))
+
+	graph, err = analysis.Network(corpus, analysis.NetworkTurn, analysis.Filter{}, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := graph.Write(os.Stdout, analysis.GraphFormatCSV); err != nil {
+		log.Fatal(err)
+	}
+
+	graph, err = analysis.Network(corpus, analysis.NetworkCoAttendance, analysis.Filter{}, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(graph.Directed, graph.Edges)
+	// Output:
+	// 1 0
+	// source,target,weight,source_label,target_label
+	// 5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a,髙谷教育課程課長,1,荒瀬克己,髙谷教育課程課長
+	// 髙谷教育課程課長,5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a,1,髙谷教育課程課長,荒瀬克己
+	// false [{5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a 髙谷教育課程課長 1}]
+}
+
+func ExampleTerms() {
+	corpus := exampleCorpus()
+
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range corpus.Minutes {
+		if err := tokenizer.TokenizeMinutes(&corpus.Minutes[i]); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	options := analysis.TermOptions{GroupBy: analysis.GroupByRole, Unit: analysis.TermUnitToken, N: 1, POS: analysis.DefaultTermPOS, Stopwords: map[string]bool{"説明": true}}
+	rows, err := analysis.Terms(corpus, analysis.Filter{}, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rows, err = analysis.TopTerms(rows, analysis.TermMeasureTFIDF, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Printf("%v %v %v %.3f\n", row.Group, row.Term, row.Count, row.TFIDF)
+	}
+
+	options = analysis.TermOptions{GroupBy: analysis.GroupByAll, Unit: analysis.TermUnitChar, N: 2}
+	rows, err = analysis.Terms(corpus, analysis.Filter{}, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(rows[0].Term, rows[0].Count)
+	// Output:
+	// member 拍手 1 0.231
+	// member 移る 1 0.231
+	// secretariat GIGA 1 0.231
+	// secretariat スクール 1 0.231
+	// ます 2
+}
+
+func ExampleLDA() {
+	corpus := exampleCorpus()
+
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := range corpus.Minutes {
+		if err := tokenizer.TokenizeMinutes(&corpus.Minutes[i]); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	options := analysis.LDAOptions{K: 2, Iterations: 50, Seed: 1}
+	options.Terms = analysis.TermOptions{Unit: analysis.TermUnitToken, POS: analysis.DefaultTermPOS}
+	lda, err := analysis.LDA(corpus, analysis.Filter{}, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(lda.Vocabulary)
+	fmt.Println(len(lda.TopicWords(3)))
+
+	for _, mixture := range lda.PersonTopics() {
+		sum := 0.0
+		for _, p := range mixture.Topics {
+			sum += p
+		}
+		fmt.Printf("%v %v %.2f\n", mixture.Key, mixture.Words, sum)
+	}
+	fmt.Println(len(lda.SpeechTopics()), len(lda.MeetingTopics()))
+	// Output:
+	// [議題 移る GIGA スクール 構想 説明 拍手]
+	// 6
+	// [5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a 荒瀬克己] 3 1.00
+	// [ 髙谷教育課程課長] 4 1.00
+	// 3 1
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+func ExampleParseJapaneseDate() {
+	for _, s := range []string{
+		"令和２年９月２８日（月曜日）１０時００分～１２時００分",
+		"令和元年五月七日",
+		"2015年4月",
+	} {
+		date, ok := model.ParseJapaneseDate(s)
+		fmt.Println(date, ok)
+	}
+	// Output:
+	// 2020-09-28 true
+	// 2019-05-07 true
+	// 2015-04 true
+}
+
+func ExampleParsePeriod() {
+	fmt.Println(model.ParsePeriod("教育課程企画特別部会（平成27年2月～29年3月）"))
+	fmt.Println(model.ParsePeriod("設置期間：平成30年12月21日～令和3年1月26日"))
+	fmt.Println(model.ParsePeriod("令和2年4月設置"))
+	// Output:
+	// 2015-02 2017-03
+	// 2018-12-21 2021-01-26
+	// 2020-04
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// Shift_JIS で表現できない文字は置き換えられ、その位置が記録される
+func ExampleNewCSVWriter_shiftJIS() {
+	var buf bytes.Buffer
+	writer, err := model.NewCSVWriter(&buf, model.EncodingShiftJIS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	writer.Write([]string{"Speaker.Label"})
+	writer.Write([]string{"髙谷課長"})
+	writer.Write([]string{"山﨑委員"})
+	writer.Flush()
+
+	for _, issue := range writer.Issues {
+		fmt.Println(issue)
+	}
+	// Output:
+	// 2行1列「髙谷課長」中の「髙」(U+9AD9)
+	// 3行1列「山﨑委員」中の「﨑」(U+FA11)
+}
+
+// CP932 では IBM拡張文字も表現できる
+func ExampleNewCSVWriter_cp932() {
+	var buf bytes.Buffer
+	writer, err := model.NewCSVWriter(&buf, model.EncodingCP932)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	writer.Write([]string{"髙谷課長", "山﨑委員"})
+	writer.Flush()
+
+	fmt.Println(len(writer.Issues))
+	// Output: 0
+}
+
+func ExampleNewCSVWriter_utf8BOM() {
+	var buf bytes.Buffer
+	writer, err := model.NewCSVWriter(&buf, model.EncodingUTF8BOM)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	writer.Write([]string{"髙谷課長"})
+	writer.Flush()
+
+	fmt.Printf("% x\n", buf.Bytes()[:3])
+	// Output: ef bb bf
+}
+
+// テキストも、Shift_JIS で表現できない文字は置き換えられ、その位置が記録される
+func ExampleNewTextWriter_shiftJIS() {
+	var buf bytes.Buffer
+	writer, err := model.NewTextWriter(&buf, model.EncodingShiftJIS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	writer.WriteLine("<h3>荒瀬部会長</h3>")
+	writer.WriteLine("<h3>髙谷課長</h3>")
+	writer.Flush()
+
+	for _, issue := range writer.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Println(buf.Len())
+	// Output:
+	// 2行1列「<h3>髙谷課長</h3>」中の「髙」(U+9AD9)
+	// 38
+}
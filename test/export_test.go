@@ -0,0 +1,325 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/export"
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// examplePersonID は、exampleCorpus の委員の人物IDです。名簿の委員と同じく、氏名から生成したものではない固定のIDを使います。
+const examplePersonID = "5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a"
+
+// exampleCorpus は、エクスポートの例で使用する小さな Corpus を返す関数です。
+func exampleCorpus() *model.Corpus {
+	person := model.Person{ID: examplePersonID, Name: "荒瀬克己", Label: "荒瀬克己部会長", Role: "部会長", Affiliation: "関西国際大学学長"}
+	chair := &model.Speaker{Label: "荒瀬部会長", Person: person, ResolutionScore: 0.9}
+	staff := &model.Speaker{Label: "髙谷教育課程課長"}
+
+	wg := model.WorkingGroup{ID: "083", Name: "新しい時代の初等中等教育の在り方特別部会"}
+
+	return &model.Corpus{
+		WorkingGroups: model.WorkingGroupList{"083": wg},
+		MemberLists: []model.MemberList{
+			{WorkingGroup: &wg, Members: []*model.Person{&person}},
+		},
+		Minutes: model.MinutesArray{
+			{
+				ID:             "wg083-013",
+				MeetingNumber:  13,
+				Title:          "新しい時代の初等中等教育の在り方特別部会（第１３回）　議事録",
+				WorkingGroup:   "新しい時代の初等中等教育の在り方特別部会",
+				WorkingGroupID: "083",
+				Date:           "2020-09-28",
+				Speakers:       map[string]*model.Speaker{chair.Label: chair, staff.Label: staff},
+				Speaches: []*model.Speach{
+					{MeetingID: "wg083-013", Turn: 1, Speaker: chair, Talks: []string{"それでは、議題１に移ります。"}},
+					{MeetingID: "wg083-013", Turn: 2, Speaker: staff, Talks: []string{"GIGAスクール構想について説明いたします。"}},
+					{MeetingID: "wg083-013", Turn: 3, Speaker: chair, Talks: []string{"（拍手）"}},
+				},
+			},
+		},
+	}
+}
+
+func ExampleSQLite() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "meroku.db")
+	if err := export.SQLite(exampleCorpus(), filePath); err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	var meetingID, label string
+	var turn int
+	row := db.QueryRow(`SELECT s.meeting_id, s.turn, s.speaker_label FROM speeches_fts JOIN speeches s ON s.id = speeches_fts.rowid WHERE speeches_fts MATCH 'GIGAスクール'`)
+	if err := row.Scan(&meetingID, &turn, &label); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(meetingID, turn, label)
+
+	var name, role string
+	row = db.QueryRow(`SELECT p.name, m.role FROM memberships m JOIN persons p ON p.id = m.person_id WHERE m.wg_id = '083'`)
+	if err := row.Scan(&name, &role); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(name, role)
+
+	// trigram は3文字未満の語を MATCH で検索できないため、2文字の語は LIKE で検索する
+	var matched, liked int
+	if err := db.QueryRow(`SELECT count(*) FROM speeches_fts WHERE speeches_fts MATCH '議題'`).Scan(&matched); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT count(*) FROM speeches_fts WHERE text LIKE '%議題%'`).Scan(&liked); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(matched, liked)
+	// Output:
+	// wg083-013 2 髙谷教育課程課長
+	// 荒瀬克己 部会長
+	// 0 1
+}
+
+func ExampleTEI() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := export.TEI(exampleCorpus(), dir); err != nil {
+		log.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "wg083-013.xml"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "<seg ") || strings.HasPrefix(line, "<note") {
+			fmt.Println(line)
+		}
+	}
+
+	raw, err = ioutil.ReadFile(filepath.Join(dir, "listPerson.xml"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.Contains(string(raw), `<person xml:id="person.`+examplePersonID+`">`))
+	// Output:
+	// <note type="speaker">荒瀬部会長</note>
+	// <seg xml:id="wg083-013.u1.1">それでは、議題１に移ります。</seg>
+	// <note type="speaker">髙谷教育課程課長</note>
+	// <seg xml:id="wg083-013.u2.1">GIGAスクール構想について説明いたします。</seg>
+	// <note type="speaker">荒瀬部会長</note>
+	// <note type="comment">（拍手）</note>
+	// true
+}
+
+func ExampleMinutesArray_ExportAsKH() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// ワーキンググループリストにないWGは、IDを見出しにして書き出す
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	if _, err := exampleCorpus().Minutes.ExportAsKH(model.WorkingGroupList{}, dir, model.EncodingUTF8); err != nil {
+		log.Fatal(err)
+	}
+
+	text, err := ioutil.ReadFile(filepath.Join(dir, "all_khcoder.txt"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(strings.SplitAfterN(string(text), "\n", 2)[0])
+
+	variables, err := ioutil.ReadFile(filepath.Join(dir, model.KHVariablesFileName))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(strings.Replace(string(variables), examplePersonID, "<person_id>", -1))
+	// Output:
+	// <h1>083</h1>
+	// speaker_label,person_id,role_class,affiliation,date,wg_id,meeting_id
+	// 荒瀬部会長,<person_id>,member,関西国際大学学長,2020-09-28,083,wg083-013
+	// 髙谷教育課程課長,,secretariat,,2020-09-28,083,wg083-013
+	// 荒瀬部会長,<person_id>,member,関西国際大学学長,2020-09-28,083,wg083-013
+}
+
+// 本文と外部変数ファイルは、どちらも指定した文字コードで書き出される
+func ExampleMinutesArray_ExportAsKH_shiftJIS() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	issues, err := exampleCorpus().Minutes.ExportAsKH(model.WorkingGroupList{}, dir, model.EncodingShiftJIS)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, issue := range issues {
+		fmt.Println(issue.Char)
+	}
+
+	for _, name := range []string{"all_khcoder.txt", model.KHVariablesFileName} {
+		encoded, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			log.Fatal(err)
+		}
+		decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(encoded)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(name, strings.Contains(string(decoded), "〓谷教育課程課長"))
+	}
+	// Output:
+	// 髙
+	// 髙
+	// all_khcoder.txt true
+	// all_khcoder_variables.csv true
+}
+
+func ExampleRDF() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "meroku.nt")
+	if err := export.RDF(exampleCorpus(), filePath, export.RDFNTriples, "http://example.org/meroku/"); err != nil {
+		log.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if strings.HasPrefix(line, "<http://example.org/meroku/meeting/wg083-013/speech/2>") {
+			fmt.Println(line)
+		}
+		if strings.Contains(line, "https://schema.org/description") {
+			fmt.Println(strings.Replace(line, examplePersonID, "<person_id>", 1))
+		}
+	}
+	// Output:
+	// <http://example.org/meroku/person/<person_id>> <https://schema.org/description> "関西国際大学学長"@ja .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://purl.org/linkedpolitics/vocabulary/Speech> .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://purl.org/dc/terms/isPartOf> <http://example.org/meroku/meeting/wg083-013> .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://purl.org/linkedpolitics/vocabulary/speaker> <http://example.org/meroku/meeting/wg083-013/speaker/%E9%AB%99%E8%B0%B7%E6%95%99%E8%82%B2%E8%AA%B2%E7%A8%8B%E8%AA%B2%E9%95%B7> .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://purl.org/linkedpolitics/vocabulary/text> "GIGAスクール構想について説明いたします。"@ja .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <https://schema.org/position> "2"^^<http://www.w3.org/2001/XMLSchema#integer> .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://example.org/meroku/vocab/speakerLabel> "髙谷教育課程課長"@ja .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://example.org/meroku/vocab/roleClass> "secretariat" .
+	// <http://example.org/meroku/meeting/wg083-013/speech/2> <http://purl.org/dc/terms/date> "2020-09-28"^^<http://www.w3.org/2001/XMLSchema#date> .
+}
+
+func ExampleXLSX() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "meroku.xlsx")
+	if err := export.XLSX(exampleCorpus(), filePath); err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	fmt.Println(f.GetSheetList())
+	rows, err := f.GetRows("speakers")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		fmt.Println(row[1], row[4], row[5])
+	}
+	// Output:
+	// [working_groups meetings persons speakers speeches]
+	// label resolution_score role_class
+	// 荒瀬部会長 0.9 member
+	// 髙谷教育課程課長 0 secretariat
+}
+
+func ExampleCoNLLU() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 改ページで文の途中から次の段落に分かれた発言
+	corpus := exampleCorpus()
+	speach := corpus.Minutes[0].Speaches[1]
+	speach.Talks = []string{"資料を", "御覧ください。", "（拍手）"}
+	tokenizer.TokenizeMinutes(&corpus.Minutes[0])
+	corpus.Minutes[0].Speaches = []*model.Speach{speach}
+
+	filePath := filepath.Join(dir, "meroku.conllu")
+	if err := export.CoNLLU(corpus, filePath); err != nil {
+		log.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(string(raw))
+	// Output:
+	// # newdoc id = wg083-013
+	// # sent_id = wg083-013-2-1
+	// # meeting = wg083-013
+	// # speaker = 髙谷教育課程課長
+	// # text = 資料を御覧ください。
+	// 1	資料	資料	NOUN	名詞-一般	_	_	_	_	SpaceAfter=No|Reading=シリョウ
+	// 2	を	を	ADP	助詞-格助詞-一般	_	_	_	_	SpaceAfter=No|Reading=ヲ
+	// 3	御覧	御覧	NOUN	名詞-動詞非自立的	_	_	_	_	SpaceAfter=No|Reading=ゴラン
+	// 4	ください	くださる	VERB	動詞-自立	_	_	_	_	SpaceAfter=No|Reading=クダサイ
+	// 5	。	。	PUNCT	記号-句点	_	_	_	_	SpaceAfter=No|Reading=。
+	//
+	// # sent_id = wg083-013-2-2
+	// # meeting = wg083-013
+	// # speaker = 髙谷教育課程課長
+	// # text = （拍手）
+	// 1	（	（	PUNCT	記号-括弧開	_	_	_	_	SpaceAfter=No|Reading=（
+	// 2	拍手	拍手	NOUN	名詞-サ変接続	_	_	_	_	SpaceAfter=No|Reading=ハクシュ
+	// 3	）	）	PUNCT	記号-括弧閉	_	_	_	_	SpaceAfter=No|Reading=）
+}
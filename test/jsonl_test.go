@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+// JSON Lines に書き出した議事録を、パースし直さずに読み込む場合
+func ExampleReadMinutesJSONL() {
+	outputdir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(outputdir)
+
+	speaker := &model.Speaker{Label: "荒瀬部会長"}
+	minutes := model.Minutes{
+		ID:       "wg083-013",
+		Speakers: map[string]*model.Speaker{speaker.Label: speaker},
+		Speaches: []*model.Speach{
+			{MeetingID: "wg083-013", Turn: 0, Speaker: speaker, Talks: []string{"それでは、開会します。"}},
+		},
+	}
+
+	writer, err := model.NewJSONLWriter(outputdir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writer.WriteMinutes(&minutes)
+	writer.Close()
+
+	minutesArray, err := model.LoadMinutesArray(outputdir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loaded := minutesArray[0]
+	fmt.Println(loaded.ID, loaded.Speaches[0].Speaker == loaded.Speakers["荒瀬部会長"])
+
+	model.ScanSpeechRecordsJSONL(filepath.Join(outputdir, model.SpeechJSONLFileName), func(record model.SpeechRecord) error {
+		fmt.Println(record.MeetingID, record.SpeakerLabel, record.Text)
+		return nil
+	})
+	// Output:
+	// wg083-013 true
+	// wg083-013 荒瀬部会長 それでは、開会します。
+}
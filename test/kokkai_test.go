@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+func ExampleImportKokkaiJSON() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// 国会会議録検索システムAPIの発言単位出力
+	response := `{
+  "numberOfRecords": 3,
+  "numberOfReturn": 3,
+  "startRecord": 1,
+  "nextRecordPosition": null,
+  "speechRecord": [
+    {"speechID": "121105124X00320230308_002", "issueID": "121105124X00320230308", "imageKind": "会議録", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 2, "speaker": "永岡桂子", "speakerYomi": "ながおかけいこ", "speakerGroup": null, "speakerPosition": "文部科学大臣", "speakerRole": null, "speech": "○永岡国務大臣　お答えいたします。\r\n　教員の働き方改革を進めてまいります。", "startPage": 1},
+    {"speechID": "121105124X00320230308_000", "issueID": "121105124X00320230308", "imageKind": "会議録", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 0, "speaker": "会議録情報", "speakerYomi": null, "speakerGroup": null, "speakerPosition": null, "speakerRole": null, "speech": "令和五年三月八日（水曜日）", "startPage": 1},
+    {"speechID": "121105124X00320230308_001", "issueID": "121105124X00320230308", "imageKind": "会議録", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 1, "speaker": "宮内秀樹", "speakerYomi": "みやうちひでき", "speakerGroup": "自由民主党・無所属の会", "speakerPosition": "委員長", "speakerRole": null, "speech": "○宮内委員長　これより会議を開きます。", "startPage": 1}
+  ]
+}`
+	filePath := filepath.Join(dir, "speech.json")
+	if err := ioutil.WriteFile(filePath, []byte(response), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	minutesArray, err := model.ImportKokkaiJSON(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	minutes := minutesArray[0]
+	fmt.Println(minutes.ID, minutes.Title, minutes.MeetingNumber, minutes.Date)
+	for _, speach := range minutes.Speaches {
+		fmt.Println(speach.Turn, speach.Speaker.Label, speach.Speaker.Person.Name, speach.Speaker.Person.Role, speach.Talks)
+	}
+
+	// 国会議員の人物IDは、審議会の人物とは別の名前空間で生成する
+	person := minutes.Speaches[0].Speaker.Person
+	fmt.Println(person.ID == model.KokkaiPersonID(person.Name), person.ID == model.PersonID(person.Name))
+	// Output:
+	// 121105124X00320230308 衆議院 文部科学委員会 第3号 3 2023-03-08
+	// 1 宮内委員長 宮内秀樹 委員長 [これより会議を開きます。]
+	// 2 永岡国務大臣 永岡桂子 文部科学大臣 [お答えいたします。 教員の働き方改革を進めてまいります。]
+	// true false
+}
+
+// 発言単位出力が複数のファイルに分かれている場合は、同じ issueID の発言を1つの会議にまとめる
+func ExampleImportKokkaiJSON_pages() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pages := map[string]string{
+		"speech_001.json": `{"numberOfRecords": 3, "numberOfReturn": 2, "startRecord": 1, "nextRecordPosition": 3, "speechRecord": [
+    {"speechID": "121105124X00320230308_001", "issueID": "121105124X00320230308", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 1, "speaker": "宮内秀樹", "speakerPosition": "委員長", "speech": "○宮内委員長　これより会議を開きます。"},
+    {"speechID": "121105124X00320230308_002", "issueID": "121105124X00320230308", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 2, "speaker": "永岡桂子", "speakerPosition": "文部科学大臣", "speech": "○永岡国務大臣　お答えいたします。"}
+  ]}`,
+		"speech_002.json": `{"numberOfRecords": 3, "numberOfReturn": 1, "startRecord": 3, "nextRecordPosition": null, "speechRecord": [
+    {"speechID": "121105124X00320230308_003", "issueID": "121105124X00320230308", "nameOfHouse": "衆議院", "nameOfMeeting": "文部科学委員会", "issue": "第3号", "date": "2023-03-08", "speechOrder": 3, "speaker": "宮内秀樹", "speakerPosition": "委員長", "speech": "○宮内委員長　本日は、これにて散会いたします。"}
+  ]}`,
+	}
+	for name, page := range pages {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(page), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	minutesArray, err := model.ImportKokkaiJSON(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(len(minutesArray), filepath.Base(minutesArray[0].Provenance.LocalPath))
+	for _, speach := range minutesArray[0].Speaches {
+		fmt.Println(speach.Turn, speach.Speaker.Label, speach.Talks)
+	}
+	// Output:
+	// 1 speech_001.json
+	// 1 宮内委員長 [これより会議を開きます。]
+	// 2 永岡国務大臣 [お答えいたします。]
+	// 3 宮内委員長 [本日は、これにて散会いたします。]
+}
+
+func ExampleMinutes_KokkaiRecord() {
+	record := exampleCorpus().Minutes[0].KokkaiRecord()
+
+	fmt.Println(record.NameOfHouse, record.Issue)
+	for _, speechRecord := range record.SpeechRecord[1:] {
+		fmt.Println(speechRecord.SpeechID, speechRecord.Speaker, speechRecord.Speech)
+	}
+	// Output:
+	// 中央教育審議会 第13回
+	// wg083-013_001 荒瀬克己 ○荒瀬部会長　それでは、議題１に移ります。
+	// wg083-013_002 髙谷教育課程課長 ○髙谷教育課程課長　GIGAスクール構想について説明いたします。
+	// wg083-013_003 荒瀬克己 ○荒瀬部会長　（拍手）
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+func ExampleParseMeetingNumber() {
+	titles := []string{
+		"新しい時代の初等中等教育の在り方特別部会（第１３回）　議事録",
+		"教育課程企画特別部会（第二十一回）議事録",
+		"教員養成部会（第105回）議事録",
+		"合同会議　議事録",
+	}
+
+	for _, title := range titles {
+		number, ok := model.ParseMeetingNumber(title)
+		fmt.Println(number, ok)
+	}
+	// Output:
+	// 13 true
+	// 21 true
+	// 105 true
+	// 0 false
+}
+
+func ExampleParseJapaneseNumber() {
+	for _, s := range []string{"十", "百五", "一二", "千二十", "３０"} {
+		number, _ := model.ParseJapaneseNumber(s)
+		fmt.Println(number)
+	}
+	// Output:
+	// 10
+	// 105
+	// 12
+	// 1020
+	// 30
+}
+
+func ExampleMeetingID() {
+	fmt.Println(model.MeetingID("083", 0, 13))
+	fmt.Println(model.MeetingID("061", 11, 1))
+	// Output:
+	// wg083-013
+	// wg061-t11-001
+}
+
+// 期が替わって回数が数え直された会議や、同じ会議が重複して取得された場合も、会議IDは重複しない
+func ExampleImportMinutesArrayFromHTML_meetingID() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	htmlDir := filepath.Join(dir, "html")
+	outputDir := filepath.Join(dir, "out")
+	for _, d := range []string{htmlDir, outputDir} {
+		if err := os.Mkdir(d, 0777); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	pages := map[string]string{
+		"wg061-1400001_001.htm": "初等中等教育分科会（第１０期）（第１回）　議事録",
+		"wg061-1400002_001.htm": "初等中等教育分科会（第１１期）（第１回）　議事録",
+		"wg061-1400003_001.htm": "初等中等教育分科会（第１１期）（第１回）　議事録",
+	}
+	for name, title := range pages {
+		html := `<html><body><div id="contentsMain"><h1>` + title + `</h1><h2>議事録</h2><p>【委員】　発言します。</p></div></body></html>`
+		if err := ioutil.WriteFile(filepath.Join(htmlDir, name), []byte(html), 0666); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	minutesArray := model.ImportMinutesArrayFromHTML([]string{htmlDir}, outputDir, model.SourceIndex{})
+	for _, minutes := range minutesArray {
+		fmt.Println(minutes.ID, minutes.Term, minutes.MeetingNumber, minutes.Speaches[0].MeetingID)
+	}
+	// Output:
+	// Processing: wg061-1400001_001.htm
+	// Processing: wg061-1400002_001.htm
+	// Processing: wg061-1400003_001.htm
+	// wg061-t10-001 10 1 wg061-t10-001
+	// wg061-t11-001 11 1 wg061-t11-001
+	// wg061-t11-001-2 11 1 wg061-t11-001-2
+}
+
+func ExampleParseMeetingTerm() {
+	for _, title := range []string{"初等中等教育分科会（第１１期）（第１回）　議事録", "教員養成部会（第105回）議事録"} {
+		term, ok := model.ParseMeetingTerm(title)
+		fmt.Println(term, ok)
+	}
+	// Output:
+	// 11 true
+	// 0 false
+}
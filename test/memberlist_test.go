@@ -50,3 +50,22 @@ func ExampleLoadMemberListFromURL() {
 	fmt.Printf("%v", len(memberList.Members))
 	//Output: 38
 }
+
+// 読み込んだ名簿には、入力ファイルの来歴情報が記録される
+func ExampleLoadMemberListFromHTML_provenance() {
+	baseDir := "../data/example/memberlist"
+	filepath := filepath.Join(baseDir, "example01.htm")
+
+	memberList, err := model.LoadMemberListFromHTML(filepath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(memberList.Provenance.LocalPath)
+	fmt.Println(memberList.Provenance.Parser)
+	fmt.Println(len(memberList.Provenance.SHA256))
+	//Output:
+	//../data/example/memberlist/example01.htm
+	//ParseMemberListFromHTML
+	//64
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/model"
+	"github.com/tsunekawa/meroku/internal/morph"
+)
+
+func ExampleTokenizer_Tokenize() {
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, token := range tokenizer.Tokenize("教員の働き方を見直しました。") {
+		fmt.Println(token.Surface, token.BaseForm, token.POS, token.Reading)
+	}
+	// Output:
+	// 教員 教員 名詞-一般 キョウイン
+	// の の 助詞-連体化 ノ
+	// 働き 働く 動詞-自立 ハタラキ
+	// 方 方 名詞-接尾-特殊 カタ
+	// を を 助詞-格助詞-一般 ヲ
+	// 見直し 見直す 動詞-自立 ミナオシ
+	// まし ます 助動詞 マシ
+	// た た 助動詞 タ
+	// 。 。 記号-句点 。
+}
+
+func ExampleMinutesArray_AttachTokens() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokenizer, err := morph.NewTokenizer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 解析結果を tokens.jsonl に保存する
+	minutes := exampleCorpus().Minutes[0]
+	tokenizer.TokenizeMinutes(&minutes)
+	writer, err := model.NewTokenWriter(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writer.WriteMinutes(&minutes)
+	writer.Close()
+
+	// 別に読み込んだ議事録に、保存した解析結果を設定する
+	minutesArray := exampleCorpus().Minutes
+	if err := minutesArray.AttachTokens(filepath.Join(dir, model.TokensJSONLFileName)); err != nil {
+		log.Fatal(err)
+	}
+	for _, token := range minutesArray[0].Speaches[1].Tokens[:3] {
+		fmt.Println(token.Surface, token.POS)
+	}
+	// Output:
+	// GIGA 名詞-固有名詞-組織
+	// スクール 名詞-一般
+	// 構想 名詞-サ変接続
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/search"
+)
+
+func ExampleIndex_Search() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	indexPath := filepath.Join(dir, search.IndexDirName)
+	count, err := search.BuildIndex(exampleCorpus().Minutes, indexPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(count)
+
+	index, err := search.Open(indexPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer index.Close()
+
+	result, err := index.Search(search.Request{Query: "GIGAスクール", Facets: []string{"role"}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(result.Total, result.Hits[0].MeetingID, result.Hits[0].Turn, result.Hits[0].SpeakerLabel)
+	fmt.Println(search.PlainFragment(result.Hits[0].Fragments[0]))
+	fmt.Println(result.Facets["role"])
+
+	result, err = index.Search(search.Request{Speaker: "荒瀬克己", Year: "2020"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, hit := range result.Hits {
+		fmt.Println(hit.Turn, hit.Speaker, hit.Text)
+	}
+	// Output:
+	// 3
+	// 1 wg083-013 2 髙谷教育課程課長
+	// 【GIGAスクール】構想について説明いたします。
+	// [{secretariat 1}]
+	// 1 荒瀬克己 それでは、議題１に移ります。
+	// 3 荒瀬克己 （拍手）
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/tsunekawa/meroku/internal/search"
+	"github.com/tsunekawa/meroku/internal/server"
+)
+
+func ExampleServer() {
+	ts := httptest.NewServer(server.New(exampleCorpus(), nil))
+	defer ts.Close()
+
+	for _, path := range []string{
+		"/api/meetings?wg=083&from=2020",
+		"/api/speeches?speaker=荒瀬克己&limit=1&offset=1",
+		"/api/persons/unknown",
+		"/api/search?q=GIGA",
+	} {
+		res, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(res.StatusCode, " ", string(body))
+	}
+	// Output:
+	// 200 {"Total":1,"Offset":0,"Limit":50,"Items":[{"ID":"wg083-013","WorkingGroupID":"083","MeetingNumber":13,"Title":"新しい時代の初等中等教育の在り方特別部会（第１３回）　議事録","Date":"2020-09-28","Venue":"","SpeechCount":3}]}
+	// 200 {"Total":2,"Offset":1,"Limit":1,"Items":[{"MeetingID":"wg083-013","WorkingGroupID":"083","Date":"2020-09-28","Turn":3,"SpeakerLabel":"荒瀬部会長","PersonID":"5e2a9d4c-7b13-4f86-a0c2-91d8e6b3f47a","RoleClass":"member","Text":"（拍手）","CharCount":4,"SentenceCount":1}]}
+	// 404 {"Error":"人物 unknown は見つかりません。"}
+	// 503 {"Error":"検索インデックスがありません。index コマンドで作成してください。"}
+}
+
+// 検索インデックスを指定した場合
+func ExampleServer_search() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	indexPath := filepath.Join(dir, search.IndexDirName)
+	if _, err := search.BuildIndex(exampleCorpus().Minutes, indexPath); err != nil {
+		log.Fatal(err)
+	}
+	index, err := search.Open(indexPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer index.Close()
+
+	ts := httptest.NewServer(server.New(exampleCorpus(), index))
+	defer ts.Close()
+
+	for _, path := range []string{
+		"/api/search?q=GIGA&facets=role",
+		"/api/search?q=GIGA&facets=unknown",
+	} {
+		res, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var page struct {
+			Total  int
+			Offset int
+			Limit  int
+			Items  []search.Hit
+			Facets map[string][]search.FacetCount
+			Error  string
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%v %v %v %v %v %v %q\n", res.StatusCode, page.Total, page.Offset, page.Limit, len(page.Items), page.Facets["role"], page.Error)
+		for _, hit := range page.Items {
+			fmt.Println(hit.MeetingID, hit.Turn, hit.SpeakerLabel, hit.Fragments)
+		}
+	}
+	// Output:
+	// 200 1 0 50 1 [{secretariat 1}] ""
+	// wg083-013 2 髙谷教育課程課長 [<mark>GIGA</mark>スクール構想について説明いたします。]
+	// 400 0 0 0 0 [] "ファセット unknown には対応していません。"
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/site"
+)
+
+func ExampleGenerate() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := site.Generate(exampleCorpus(), dir); err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.html"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, file := range files {
+		rel, _ := filepath.Rel(dir, file)
+		fmt.Println(strings.Replace(filepath.ToSlash(rel), examplePersonID, "<person_id>", 1))
+	}
+
+	// 人物ページには、その人物の発言が会議へのリンクとともに表示される
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "person", examplePersonID+".html"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.Contains(string(raw), `<a href="../meeting/wg083-013.html#t3">荒瀬部会長</a>`))
+	// Output:
+	// meeting/wg083-013.html
+	// person/<person_id>.html
+	// wg/083.html
+	// true
+}
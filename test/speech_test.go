@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsunekawa/meroku/internal/model"
+)
+
+func ExampleSplitSentences() {
+	for _, sentence := range model.SplitSentences("ありがとうございました。本当ですか？「異議なし」と言いました。）以上") {
+		fmt.Println(sentence)
+	}
+	// Output:
+	// ありがとうございました。
+	// 本当ですか？
+	// 「異議なし」と言いました。）
+	// 以上
+}
+
+func ExampleRoleClass() {
+	speakers := []*model.Speaker{
+		{Label: "荒瀬部会長"},
+		{Label: "髙谷教育課程課長"},
+		{Label: "山田参考人"},
+		{Label: "鈴木", Person: model.Person{ID: "test-0001"}},
+	}
+
+	for _, speaker := range speakers {
+		fmt.Println(model.RoleClass(speaker))
+	}
+	// Output:
+	// member
+	// secretariat
+	// other
+	// member
+}
+
+func ExampleMinutes_SpeechRecords() {
+	minutes := model.Minutes{
+		ID:             "wg083-013",
+		WorkingGroupID: "083",
+		Date:           "2020-09-28",
+		Speaches: []*model.Speach{
+			{Turn: 0, Talks: []string{"１．日時"}},
+			{Turn: 1, Speaker: &model.Speaker{Label: "荒瀬部会長"}, Talks: []string{"　それでは、開会します。", "議題１に移ります。"}},
+		},
+	}
+
+	for _, record := range minutes.SpeechRecords() {
+		fmt.Println(record.MeetingID, record.Turn, record.SpeakerLabel, record.RoleClass, record.CharCount, record.SentenceCount)
+	}
+	// Output: wg083-013 1 荒瀬部会長 member 20 2
+}
+
+func ExampleMinutesArray_ExportSpeechesAsTable() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	minutesArray := model.MinutesArray{
+		{
+			ID:             "wg083-013",
+			WorkingGroupID: "083",
+			Date:           "2020-09-28",
+			Speaches: []*model.Speach{
+				{Turn: 1, Speaker: &model.Speaker{Label: "荒瀬部会長"}, Talks: []string{"それでは、開会します。", "議題１に移ります。"}},
+				{Turn: 2, Speaker: &model.Speaker{Label: "﨑山委員", Person: model.Person{ID: "test-0001"}}, Talks: []string{"「資料１, 資料２」について\t質問します。"}},
+			},
+		},
+	}
+
+	for _, format := range []string{"csv", "tsv"} {
+		if err := minutesArray.ExportSpeechesAsTable(dir, format); err != nil {
+			log.Fatal(err)
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(dir, "all_speech."+format))
+		if err != nil {
+			log.Fatal(err)
+		}
+		// タブは \t と表示する
+		fmt.Print(strings.ReplaceAll(string(raw), "\t", `\t`))
+	}
+
+	fmt.Println(minutesArray.ExportSpeechesAsTable(dir, "xlsx"))
+	// Output:
+	// meeting_id,wg_id,date,turn,speaker_label,person_id,role_class,text,char_count,sentence_count
+	// wg083-013,083,2020-09-28,1,荒瀬部会長,,member,"それでは、開会します。
+	// 議題１に移ります。",20,2
+	// wg083-013,083,2020-09-28,2,﨑山委員,test-0001,member,"「資料１, 資料２」について\t質問します。",19,1
+	// meeting_id\twg_id\tdate\tturn\tspeaker_label\tperson_id\trole_class\ttext\tchar_count\tsentence_count
+	// wg083-013\t083\t2020-09-28\t1\t荒瀬部会長\t\tmember\tそれでは、開会します。 議題１に移ります。\t20\t2
+	// wg083-013\t083\t2020-09-28\t2\t﨑山委員\ttest-0001\tmember\t「資料１, 資料２」について 質問します。\t19\t1
+	// xlsxは対応していない形式です。(csv, tsv)
+}
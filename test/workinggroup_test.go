@@ -2,7 +2,14 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
 
 	"github.com/tsunekawa/meroku/internal/model"
 )
@@ -36,3 +43,214 @@ func ExampleWorkingGroup_GetMemberListURLs_two() {
 	//https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/meibo/1372229.htm
 	//https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/meibo/1366594.htm
 }
+
+func ExampleWorkingGroupIDFromFileName() {
+	for _, name := range []string{"html/no03wg084-1422863_00007.htm", "html/wg084-1422863_00007.htm", "index.htm"} {
+		wgID, ok := model.WorkingGroupIDFromFileName(name)
+		fmt.Println(wgID, ok)
+	}
+	// Output:
+	// 084 true
+	// 084 true
+	//  false
+}
+
+// 表示順をキーとする旧形式のダウンロードディレクトリを移行する場合
+func ExampleMigrateLegacyDownloadDir() {
+	rootDir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	legacy := `{"no03": {"Order": "no03", "ID": "084", "Name": "新しい時代の高等学校教育の在り方ワーキンググループ"}}`
+	ioutil.WriteFile(filepath.Join(rootDir, "working-groups.json"), []byte(legacy), 0666)
+	os.MkdirAll(filepath.Join(rootDir, "html"), 0777)
+	ioutil.WriteFile(filepath.Join(rootDir, "html", "no03wg084-1422863_00007.htm"), []byte{}, 0666)
+
+	if err := model.MigrateLegacyDownloadDir(rootDir); err != nil {
+		log.Fatal(err)
+	}
+
+	wgList := model.ImportWorkingGroupList(filepath.Join(rootDir, "working-groups.json"))
+	fmt.Println(wgList["084"].Name)
+
+	files, _ := ioutil.ReadDir(filepath.Join(rootDir, "html"))
+	for _, file := range files {
+		fmt.Println(file.Name())
+	}
+	// Output:
+	// 新しい時代の高等学校教育の在り方ワーキンググループ
+	// wg084-1422863_00007.htm
+}
+
+// 部会 → 特別部会 → ワーキンググループ の階層をたどる場合
+func ExampleWorkingGroupList_Descendants() {
+	wgList := model.WorkingGroupList{
+		"061": {ID: "061", Name: "教育課程部会"},
+		"053": {ID: "053", Name: "教育課程企画特別部会", ParentID: "061"},
+		"066": {ID: "066", Name: "言語能力の向上に関する特別チーム", ParentID: "053"},
+		"084": {ID: "084", Name: "新しい時代の高等学校教育の在り方ワーキンググループ"},
+	}
+
+	fmt.Println(wgList.Descendants("061"))
+	fmt.Println(wgList.Ancestors("066"))
+	fmt.Println(wgList.RootID("066"), wgList.Depth("066"))
+
+	minutesArray := model.MinutesArray{
+		{ID: "wg066-001", WorkingGroupID: "066"},
+		{ID: "wg084-001", WorkingGroupID: "084"},
+	}
+	for _, minutes := range minutesArray.FilterByWorkingGroup(wgList, "061") {
+		fmt.Println(minutes.ID)
+	}
+	// Output:
+	// [053 066]
+	// [053 061]
+	// 061 2
+	// wg066-001
+}
+
+// 会議体のページに上位・同位の会議体へのリンク（関連する会議体）が含まれる場合
+func ExampleCrawlWorkingGroups() {
+	const base = "/b_menu/shingi/chukyo/chukyo3/"
+	shingiBlock := func(ids ...string) string {
+		html := `<div class="shingi_block"><ul>`
+		for _, id := range ids {
+			html += `<li><a href="` + base + id + `/index.htm">会議体` + id + `</a></li>`
+		}
+		return html + `</ul></div>`
+	}
+	pages := map[string]string{
+		base + "index.htm": shingiBlock("061", "084"),
+		base + "061/index.htm": `<div id="contentsMain"><p>設置期間：平成30年12月21日～令和3年1月26日</p>` +
+			`<a href="giji_list/index.htm">これまでの議事要旨・議事録・配付資料の一覧はこちら</a>` +
+			`<a href="../meibo/1.htm">委員名簿</a>` +
+			shingiBlock("053") + `<h2>関連する審議会</h2>` + shingiBlock("084") + `</div>`,
+		base + "061/giji_list/index.htm": `<a href="1.htm">議事録</a><a href="2.htm">議事録</a>`,
+		base + "053/index.htm":           `<div id="contentsMain">` + shingiBlock("066", "061") + `</div>`,
+		base + "066/index.htm": `<div id="contentsMain">` + shingiBlock("084") +
+			// 議事録一覧を取得できない会議体があっても、クロールは続ける
+			`<a href="http://127.0.0.1:1/giji_list/index.htm">これまでの議事要旨・議事録・配付資料の一覧はこちら</a></div>`,
+		base + "084/index.htm":           `<div id="contentsMain">` + shingiBlock("061") + `</div>`,
+	}
+
+	var mutex sync.Mutex
+	fetches := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		fetches[r.URL.Path]++
+		mutex.Unlock()
+		page, exists := pages[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><body>`+page+`</body></html>`)
+	}))
+	defer ts.Close()
+
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	workingGroups := model.CrawlWorkingGroups(ts.URL + base + "index.htm")
+
+	ids := []string{}
+	for id := range workingGroups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		wg := workingGroups[id]
+		fmt.Printf("%v parent=%q children=%v minutes=%v memberlists=%v from=%v\n", id, wg.ParentID, wg.ChildIDs, len(wg.MinutesURLs), len(wg.MemberListURLs), wg.ActiveFrom)
+	}
+
+	paths := []string{}
+	for path := range fetches {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Println(path, fetches[path])
+	}
+	// Output:
+	// 053 parent="061" children=[066] minutes=0 memberlists=0 from=
+	// 061 parent="" children=[053] minutes=2 memberlists=1 from=2018-12-21
+	// 066 parent="053" children=[] minutes=0 memberlists=0 from=
+	// 084 parent="" children=[] minutes=0 memberlists=0 from=
+	// /b_menu/shingi/chukyo/chukyo3/053/index.htm 1
+	// /b_menu/shingi/chukyo/chukyo3/061/giji_list/index.htm 1
+	// /b_menu/shingi/chukyo/chukyo3/061/index.htm 1
+	// /b_menu/shingi/chukyo/chukyo3/066/index.htm 1
+	// /b_menu/shingi/chukyo/chukyo3/084/index.htm 1
+	// /b_menu/shingi/chukyo/chukyo3/index.htm 1
+}
+
+func ExampleCrawlArchivedWorkingGroups() {
+	const base = "/b_menu/shingi/chukyo/"
+	pages := map[string]string{
+		base + "chukyo3/index.htm": `<div class="shingi_block"><ul><li><a href="061/index.htm">教育課程部会</a></li></ul></div>` +
+			`<a href="../chukyo0/index.htm">過去の分科会・部会等</a>`,
+		base + "chukyo0/index.htm": `<div id="contentsMain"><ul>` +
+			`<li><a href="../chukyo3/010/index.htm">教育制度分科会</a>（平成13年1月6日～平成29年2月14日）</li>` +
+			`<li><a href="../chukyo3/061/index.htm">教育課程部会</a></li>` +
+			`<li><a href="/b_menu/shingi/gijyutu/gijyutu2/index.htm">科学技術・学術審議会</a></li>` +
+			`</ul><p><a href="../chukyo3/020/index.htm">関連するページ</a></p></div>`,
+		base + "chukyo3/010/index.htm": `<div id="contentsMain"><div class="shingi_block"><ul><li><a href="../011/index.htm">地方教育行政部会</a></li></ul></div></div>`,
+		base + "chukyo3/011/index.htm": `<div id="contentsMain"></div>`,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, exists := pages[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><body>`+page+`</body></html>`)
+	}))
+	defer ts.Close()
+
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	workingGroups := model.CrawlArchivedWorkingGroups(ts.URL+base+"chukyo3/index.htm", []string{})
+
+	ids := []string{}
+	for id := range workingGroups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		wg := workingGroups[id]
+		fmt.Printf("%v %v parent=%q from=%q until=%q\n", id, wg.Status, wg.ParentID, wg.ActiveFrom, wg.ActiveUntil)
+	}
+	// Output:
+	// 010 closed parent="" from="2001-01-06" until="2017-02-14"
+	// 011 closed parent="010" from="" until=""
+}
+
+// 旧形式のファイルに、URLからもIDを取得できない項目が含まれる場合
+func ExampleImportWorkingGroupList_withoutID() {
+	dir, err := ioutil.TempDir("", "meroku")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	legacy := `{
+		"no00": {"Order": "no00", "Name": "教育課程部会", "URL": "https://www.mext.go.jp/b_menu/shingi/chukyo/chukyo3/061/index.htm"},
+		"no01": {"Order": "no01", "Name": "お知らせ", "URL": "https://www.mext.go.jp/b_menu/news/index.html"},
+		"no02": {"Order": "no02", "Name": "リンク切れ"}
+	}`
+	wgListPath := filepath.Join(dir, "working-groups.json")
+	if err := ioutil.WriteFile(wgListPath, []byte(legacy), 0666); err != nil {
+		log.Fatal(err)
+	}
+
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	wgList := model.ImportWorkingGroupList(wgListPath)
+	for id, wg := range wgList {
+		fmt.Printf("%q %v\n", id, wg.Name)
+	}
+	// Output:
+	// "061" 教育課程部会
+}